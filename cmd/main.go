@@ -1,35 +1,270 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
 	controller "notification/internal/controllers/handlers"
+	"notification/internal/entity"
+	"notification/internal/platform/config"
 	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/apikey"
+	"notification/internal/usecase/dispatch"
+	"notification/internal/usecase/idempotency"
+	"notification/internal/usecase/jwtauth"
 	"notification/internal/usecase/notification"
+	"notification/internal/usecase/notifiers"
+	"notification/internal/usecase/retention"
+	"notification/internal/usecase/schedule"
+	"notification/internal/usecase/template"
+	"notification/internal/usecase/user"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/handlers"
 )
 
+// shutdownTimeout is how long requests in flight get to finish on shutdown.
+const shutdownTimeout = 30 * time.Second
+
 var (
-	url                 = "../internal/logs.txt"
 	notificationUseCase *notification.NotificationUseCase
+	dispatchUseCase     *dispatch.DispatchUseCase
+	userUseCase         *user.UserUseCase
+	templateUseCase     *template.TemplateUseCase
+	scheduleUseCase     *schedule.ScheduleUseCase
 )
 
 func main() {
-	logRepository := log.NewLogRepository(url)
-	notificationUseCase = notification.NewNotificationUseCase(logRepository)
-	StartServer()
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or JSON configuration file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath, os.Getenv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	logRepository, err := newLogRepository(cfg.Storage)
+	if err != nil {
+		fmt.Printf("Failed to open log repository: %v\n", err)
+		os.Exit(1)
+	}
+
+	userRepository := log.NewUserRepository(cfg.Storage.File("users.json"))
+	deviceRepository := log.NewDeviceRepository(cfg.Storage.File("devices.json"))
+	deadLetterRepository := log.NewDeadLetterRepository(cfg.Storage.File("dead-letters.json"))
+	templateRepository := log.NewTemplateRepository(cfg.Storage.File("templates.json"))
+	scheduleRepository := log.NewScheduleRepository(cfg.Storage.File("schedules.json"))
+	notificationUseCase = notification.NewNotificationUseCase(logRepository, userRepository, deadLetterRepository)
+	notificationUseCase.TemplateRepository = templateRepository
+	notificationUseCase.ScheduleRepository = scheduleRepository
+	notificationUseCase.CategoryFallbacks = cfg.FallbackChains
+	notificationUseCase.FallbackTimeout = cfg.FallbackTimeout.Duration()
+	notificationUseCase.RateLimits = rateLimits(cfg.RateLimits)
+	notificationUseCase.EmailUsecase = notifiers.NewEmailUsecase(emailConfig(cfg.Providers.SMTP))
+	notificationUseCase.SMSUsecase = notifiers.NewSMSUsecase(smsProvider(cfg.Providers.SMS), cfg.Providers.SMS.CountryCode)
+	notificationUseCase.PushUsecase = notifiers.NewPushUsecase(pushConfig(cfg.Providers.Push), deviceRepository)
+	userUseCase = user.NewUserUseCase(userRepository, deviceRepository)
+	templateUseCase = template.NewTemplateUseCase(templateRepository)
+
+	jobRepository := log.NewJobRepository(cfg.Storage.JobQueuePath)
+	dispatchUseCase = dispatch.NewDispatchUseCase(notificationUseCase, jobRepository, cfg.Workers.Dispatch)
+	dispatchUseCase.LowPriorityDelay = cfg.Workers.LowPriorityDelay.Duration()
+	dispatchUseCase.JobRetention = cfg.Workers.JobRetention.Duration()
+	if err := dispatchUseCase.Start(); err != nil {
+		fmt.Printf("Failed to start dispatcher: %v\n", err)
+		os.Exit(1)
+	}
+	defer dispatchUseCase.Stop()
+
+	scheduleUseCase = schedule.NewScheduleUseCase(scheduleRepository, notificationUseCase, cfg.Workers.ScheduleInterval.Duration())
+	scheduleUseCase.Retention = cfg.Workers.ScheduleRetention.Duration()
+	scheduleUseCase.Start()
+	defer scheduleUseCase.Stop()
+
+	if retentionUseCase := logRetention(logRepository, cfg.Retention); retentionUseCase != nil {
+		retentionUseCase.Start()
+		defer retentionUseCase.Stop()
+	}
+
+	// The workers above are stopped by their deferred calls once the server
+	// has shut down on SIGINT or SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := StartServer(ctx, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
+	fmt.Println("Server stopped, waiting for running jobs")
 }
 
-func StartServer() {
-	handler := controller.NewNotificationHandler(notificationUseCase)
+// StartServer serves the API until ctx is done, then shuts the server down
+// gracefully.
+func StartServer(ctx context.Context, cfg config.Config) error {
+	handler := controller.NewNotificationHandler(notificationUseCase, dispatchUseCase, scheduleUseCase)
+	idempotencyRepository := log.NewIdempotencyRepository(cfg.Storage.File("idempotency.json"))
+	handler.IdempotencyUseCase = idempotency.NewIdempotencyUseCase(idempotencyRepository, cfg.IdempotencyTTL.Duration())
 	router := handler.RegisterRoutes()
+	controller.NewUserHandler(userUseCase).RegisterRoutes(router)
+	controller.NewTemplateHandler(templateUseCase).RegisterRoutes(router)
 
-	headers := handlers.AllowedHeaders([]string{"Content-Type"})
+	if cfg.Auth.AdminAPIKey == "" {
+		fmt.Println("No admin API key is set, only issued API keys are accepted")
+	}
+	apiKeyRepository := log.NewAPIKeyRepository(cfg.Storage.File("api-keys.json"))
+	apiKeyHandler := controller.NewAPIKeyHandler(apikey.NewAPIKeyUseCase(apiKeyRepository, cfg.Auth.AdminAPIKey))
+	apiKeyHandler.RegisterRoutes(router)
+
+	verifier, err := jwtVerifier(cfg.Auth.JWT)
+	if err != nil {
+		return fmt.Errorf("Failed to configure JWT authentication: %v", err)
+	}
+	if verifier != nil {
+		router.Use(controller.NewJWTHandler(verifier).Authenticate)
+	}
+	router.Use(apiKeyHandler.Authenticate)
+
+	headers := handlers.AllowedHeaders([]string{"Content-Type", "Idempotency-Key", "Authorization", "X-API-Key"})
 	methods := handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE"})
-	origins := handlers.AllowedOrigins([]string{"*"})
+	origins := handlers.AllowedOriginValidator(allowedOrigin(cfg.Server.CORSAllowedOrigins))
+	server := &http.Server{
+		Addr:    cfg.Server.Address,
+		Handler: handlers.CORS(headers, methods, origins)(router),
+	}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		timeout, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		shutdown <- server.Shutdown(timeout)
+	}()
+
+	if cfg.Server.TLS.IsEnabled() {
+		fmt.Printf("Server listening on https://%s\n", cfg.Server.Address)
+		err = server.ListenAndServeTLS(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile)
+	} else {
+		fmt.Printf("Server listening on http://%s\n", cfg.Server.Address)
+		err = server.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return <-shutdown
+}
+
+// jwtVerifier accepts JWTs signed with one of the secrets (HS256) or a key
+// of the JWKS file, for the configured audience. JWTs aren't accepted when
+// neither is set.
+func jwtVerifier(jwt config.JWTConfig) (*jwtauth.Verifier, error) {
+	if !jwt.IsEnabled() {
+		return nil, nil
+	}
+
+	return jwtauth.NewVerifier(jwtauth.Config{
+		Secrets:     jwt.Secrets,
+		JWKSPath:    jwt.JWKSPath,
+		Audience:    jwt.Audience,
+		Tenant:      jwt.Tenant,
+		RolesClaim:  jwt.RolesClaim,
+		TenantClaim: jwt.TenantClaim,
+		Leeway:      jwt.Leeway.Duration(),
+	})
+}
+
+// allowedOrigin accepts the browser origins listed. Without any,
+// cross-origin requests are refused.
+func allowedOrigin(origins []string) func(string) bool {
+	allowed := map[string]bool{}
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(origin string) bool {
+		return allowed[origin]
+	}
+}
+
+// logRetention prunes logs older than the retention days every interval.
+// Logs are kept forever when no retention is set.
+func logRetention(logRepository log.Log, policy config.RetentionConfig) *retention.RetentionUseCase {
+	if policy.Days <= 0 {
+		return nil
+	}
+
+	return retention.NewRetentionUseCase(logRepository, time.Duration(policy.Days)*24*time.Hour, policy.Interval.Duration())
+}
+
+// rateLimits returns every channel's limits. Messages over a limit are
+// dropped unless a policy says otherwise.
+func rateLimits(configs map[entity.Channel]config.RateLimitConfig) map[entity.Channel]notification.RateLimit {
+	limits := map[entity.Channel]notification.RateLimit{}
+	for channel, limit := range configs {
+		policy := notification.RateLimitPolicy(limit.Policy)
+		if policy == "" {
+			policy = notification.DropPolicy
+		}
+
+		limits[channel] = notification.RateLimit{
+			User:      limit.User.Limit(),
+			Provider:  limit.Provider.Limit(),
+			Policy:    policy,
+			Downgrade: limit.Downgrade,
+		}
+	}
+
+	return limits
+}
+
+func newLogRepository(storage config.StorageConfig) (log.Log, error) {
+	switch storage.Logs.Backend {
+	case config.JSONLBackend:
+		return log.NewJSONLogRepository(storage.LogPath()), nil
+	case config.SQLiteBackend:
+		return log.NewSQLiteLogRepository(storage.LogPath())
+	default:
+		return log.NewLogRepository(storage.LogPath()), nil
+	}
+}
+
+func emailConfig(smtp config.SMTPConfig) notifiers.EmailConfig {
+	return notifiers.EmailConfig{
+		Host:     smtp.Host,
+		Port:     smtp.Port,
+		Username: smtp.Username,
+		Password: smtp.Password,
+		From:     smtp.From,
+		Subject:  smtp.Subject,
+		TLS:      notifiers.TLSMode(smtp.TLS),
+	}
+}
+
+func smsProvider(sms config.SMSConfig) notifiers.SMSProvider {
+	if sms.GatewayURL == "" {
+		return nil
+	}
+
+	return notifiers.NewHTTPGateway(notifiers.SMSGatewayConfig{
+		BaseURL:        sms.GatewayURL,
+		AccountID:      sms.AccountID,
+		AuthToken:      sms.AuthToken,
+		From:           sms.From,
+		StatusCallback: sms.StatusCallback,
+	})
+}
 
-	fmt.Println("Server listening on http://localhost:8080")
-	http.ListenAndServe(":8080", handlers.CORS(headers, methods, origins)(router))
+func pushConfig(push config.PushConfig) notifiers.PushConfig {
+	return notifiers.PushConfig{
+		Endpoint:    push.Endpoint,
+		AccessToken: push.AccessToken,
+		Title:       push.Title,
+	}
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"notification/internal/platform/config"
+	log "notification/internal/platform/repositories"
+	"os"
+)
+
+func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or JSON configuration file of the server")
+	from := flag.String("from", "", "pipe-delimited log file to convert, logs.txt in the data directory by default")
+	to := flag.String("to", "", "JSON Lines file to create, logs.jsonl in the data directory by default")
+	flag.Parse()
+
+	// The defaults are in the server's data directory, read from the same
+	// configuration file and environment.
+	if *from == "" || *to == "" {
+		cfg, err := config.Load(*configPath, os.Getenv)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if *from == "" {
+			*from = cfg.Storage.File("logs.txt")
+		}
+		if *to == "" {
+			*to = cfg.Storage.File("logs.jsonl")
+		}
+	}
+
+	count, err := log.MigrateTextLogs(*from, *to)
+	if err != nil {
+		fmt.Printf("Failed to migrate logs: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Migrated %d log entries from %s to %s\n", count, *from, *to)
+}
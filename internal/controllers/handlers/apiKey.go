@@ -0,0 +1,224 @@
+package notification_handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/apikey"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type apiKeyKey struct{}
+
+type APIKeyHandler struct {
+	APIKeyUseCase *apikey.APIKeyUseCase
+}
+
+type apiKeyRequest struct {
+	Name   string         `json:"name"`
+	Scopes []entity.Scope `json:"scopes"`
+}
+
+// apiKeyResponse leaves out the key's hash. Key, the secret, is only set
+// when the key is issued.
+type apiKeyResponse struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Prefix    string         `json:"prefix"`
+	Scopes    []entity.Scope `json:"scopes"`
+	CreatedAt time.Time      `json:"created_at"`
+	RevokedAt *time.Time     `json:"revoked_at,omitempty"`
+	Key       string         `json:"key,omitempty"`
+}
+
+// routeScopes is the scope each route needs, by path template. Routes that
+// aren't listed need ScopeManageKeys.
+var routeScopes = map[string]entity.Scope{
+	"/add":                                 entity.ScopeSendNotifications,
+	"/jobs/{id}":                           entity.ScopeSendNotifications,
+	"/schedules":                           entity.ScopeSendNotifications,
+	"/schedules/{id}":                      entity.ScopeSendNotifications,
+	"/dead-letters/replay":                 entity.ScopeSendNotifications,
+	"/dead-letters/{id}/replay":            entity.ScopeSendNotifications,
+	"/get":                                 entity.ScopeReadLogs,
+	"/notifications/{id}":                  entity.ScopeReadLogs,
+	"/dead-letters":                        entity.ScopeReadLogs,
+	"/dead-letters/{id}":                   entity.ScopeReadLogs,
+	"/delete":                              entity.ScopeDeleteLogs,
+	"/webhooks/{channel}":                  entity.ScopeWriteReceipts,
+	"/users":                               entity.ScopeManageUsers,
+	"/users/{id}":                          entity.ScopeManageUsers,
+	"/users/{id}/subscriptions/{category}": entity.ScopeManageUsers,
+	"/users/{id}/channels/{channel}":       entity.ScopeManageUsers,
+	"/users/{id}/devices":                  entity.ScopeManageUsers,
+	"/users/{id}/devices/{token}":          entity.ScopeManageUsers,
+	"/templates":                           entity.ScopeManageTemplates,
+	"/templates/{id}":                      entity.ScopeManageTemplates,
+}
+
+func NewAPIKeyHandler(apiKeyUseCase *apikey.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		APIKeyUseCase: apiKeyUseCase,
+	}
+}
+
+// Authenticate is a router middleware that only lets requests through whose
+// API key has the scope of the matched route. The key is read from an
+// "Authorization: Bearer" header, an X-API-Key header or, for providers that
+// only support it, the password of basic authentication, and the key is
+// passed on with the request. Requests already authenticated with a JWT
+// pass.
+func (h *APIKeyHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := requestPrincipal(r); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key, err := h.APIKeyUseCase.Authenticate(requestAPIKey(r))
+		if errors.Is(err, apikey.ErrInvalidKey) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="notifications"`)
+			writeJSONError(w, http.StatusUnauthorized, "unauthorized", "A valid API key is required")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to check API key")
+			return
+		}
+
+		if scope := requiredScope(r); !key.HasScope(scope) {
+			writeJSONError(w, http.StatusForbidden, "forbidden", fmt.Sprintf("The API key lacks the %s scope", scope))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyKey{}, key)))
+	})
+}
+
+func (h *APIKeyHandler) IssueKey(w http.ResponseWriter, r *http.Request) {
+	var requestBody apiKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	key, secret, err := h.APIKeyUseCase.IssueKey(requestBody.Name, requestBody.Scopes)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	response := toAPIKeyResponse(key)
+	response.Key = secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *APIKeyHandler) GetKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.APIKeyUseCase.GetKeys()
+	if err != nil {
+		http.Error(w, "Failed to get API keys", http.StatusInternalServerError)
+		return
+	}
+
+	responses := []apiKeyResponse{}
+	for _, key := range keys {
+		responses = append(responses, toAPIKeyResponse(key))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responses)
+}
+
+func (h *APIKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.APIKeyUseCase.RevokeKey(mux.Vars(r)["id"])
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toAPIKeyResponse(key))
+}
+
+func (h *APIKeyHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/keys", h.GetKeys).Methods(http.MethodGet)
+	router.HandleFunc("/keys", h.IssueKey).Methods(http.MethodPost)
+	router.HandleFunc("/keys/{id}", h.RevokeKey).Methods(http.MethodDelete)
+}
+
+func requestAPIKey(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	_, password, _ := r.BasicAuth()
+	return password
+}
+
+// requestKey returns the API key a request was authenticated with.
+func requestKey(r *http.Request) (entity.APIKey, bool) {
+	key, ok := r.Context().Value(apiKeyKey{}).(entity.APIKey)
+	return key, ok
+}
+
+func requiredScope(r *http.Request) entity.Scope {
+	if route := mux.CurrentRoute(r); route != nil {
+		template, _ := route.GetPathTemplate()
+		if scope, ok := routeScopes[template]; ok {
+			return scope
+		}
+	}
+
+	return entity.ScopeManageKeys
+}
+
+func toAPIKeyResponse(key entity.APIKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// writeJSONError answers with an error code and a readable message as JSON.
+func writeJSONError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}{
+		Error:   code,
+		Message: message,
+	})
+}
+
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	var validationError apikey.ValidationError
+
+	switch {
+	case errors.As(err, &validationError):
+		http.Error(w, validationError.Message, http.StatusBadRequest)
+	case errors.Is(err, log.ErrAPIKeyNotFound):
+		http.Error(w, "API key not found", http.StatusNotFound)
+	default:
+		http.Error(w, "Failed to update API keys", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,138 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/apikey"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const adminKey = "admin-secret"
+
+func TestIssueKey_Success(t *testing.T) {
+	router := setAPIKeyRouter(t)
+
+	secret := issueKey(t, router, `{"name": "reporting", "scopes": ["logs:read"]}`)
+
+	r := httptest.NewRequest(http.MethodGet, "/get", nil)
+	r.Header.Set("Authorization", "Bearer "+secret)
+	w := httptest.NewRecorder()
+	logMock.EXPECT().GetLogsPage(entity.LogFilter{}).Return([]entity.Log{}, 0, nil)
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	r = httptest.NewRequest(http.MethodDelete, "/delete", nil)
+	r.Header.Set("X-API-Key", secret)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "forbidden", decodeAuthError(t, w))
+	controller.Finish()
+}
+
+func TestRevokeKey_Success(t *testing.T) {
+	router := setAPIKeyRouter(t)
+
+	secret := issueKey(t, router, `{"name": "provider", "scopes": ["receipts:write"]}`)
+
+	r := httptest.NewRequest(http.MethodGet, "/keys", nil)
+	r.Header.Set("X-API-Key", adminKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), secret)
+
+	var keys []apiKeyResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&keys))
+	assert.Equal(t, 1, len(keys))
+
+	r = httptest.NewRequest(http.MethodDelete, "/keys/"+keys[0].ID, nil)
+	r.Header.Set("X-API-Key", adminKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	r = httptest.NewRequest(http.MethodPost, "/webhooks/sms", strings.NewReader(`{}`))
+	r.SetBasicAuth("twilio", secret)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "unauthorized", decodeAuthError(t, w))
+	controller.Finish()
+}
+
+func TestAuthenticate_Error(t *testing.T) {
+	router := setAPIKeyRouter(t)
+
+	r := httptest.NewRequest(http.MethodDelete, "/delete", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	r = httptest.NewRequest(http.MethodPost, "/keys", strings.NewReader(`{"name": "reporting", "scopes": ["logs:write"]}`))
+	r.Header.Set("X-API-Key", adminKey)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	controller.Finish()
+}
+
+func TestRouteScopes_Success(t *testing.T) {
+	router := setAPIKeyRouter(t)
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, _ := route.GetPathTemplate()
+		if _, ok := routeScopes[template]; !ok && !strings.HasPrefix(template, "/keys") {
+			t.Errorf("route %s has no scope", template)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+// setAPIKeyRouter builds the routes of every handler behind the middlewares
+// and then the API key middleware, as the server does.
+func setAPIKeyRouter(t *testing.T, middlewares ...mux.MiddlewareFunc) *mux.Router {
+	setHandlerAndLogMock(t)
+	router := handler.RegisterRoutes()
+	NewUserHandler(nil).RegisterRoutes(router)
+	NewTemplateHandler(nil).RegisterRoutes(router)
+
+	apiKeyHandler := NewAPIKeyHandler(apikey.NewAPIKeyUseCase(repositories.NewAPIKeyRepository(t.TempDir()+"/api-keys.json"), adminKey))
+	apiKeyHandler.RegisterRoutes(router)
+	router.Use(middlewares...)
+	router.Use(apiKeyHandler.Authenticate)
+	return router
+}
+
+func issueKey(t *testing.T, router *mux.Router, body string) string {
+	r := httptest.NewRequest(http.MethodPost, "/keys", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+adminKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var issued apiKeyResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&issued))
+	return issued.Key
+}
+
+func decodeAuthError(t *testing.T, w *httptest.ResponseRecorder) string {
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.NotEmpty(t, body.Message)
+	return body.Error
+}
@@ -0,0 +1,118 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/dispatch"
+	"notification/internal/usecase/notification"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *NotificationHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	deadLetters, err := h.NotificationUseCase.GetDeadLetters()
+	if err != nil {
+		http.Error(w, "Failed to get dead letters", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deadLetters)
+}
+
+func (h *NotificationHandler) GetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	deadLetter, err := h.NotificationUseCase.GetDeadLetter(mux.Vars(r)["id"])
+	if errors.Is(err, log.ErrDeadLetterNotFound) {
+		http.Error(w, "Dead letter not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get dead letter", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deadLetter)
+}
+
+func (h *NotificationHandler) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job, err := h.DispatchUseCase.ReplayDeadLetter(mux.Vars(r)["id"])
+	if errors.Is(err, log.ErrDeadLetterNotFound) {
+		http.Error(w, "Dead letter not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, notification.ErrReplayInProgress) {
+		http.Error(w, "Dead letter is already being replayed", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, dispatch.ErrStopped) {
+		http.Error(w, "Failed to queue replay", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to replay dead letter", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Message string `json:"message"`
+		JobID   string `json:"job_id"`
+	}{
+		Message: "Replay accepted",
+		JobID:   job.ID,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *NotificationHandler) ReplayDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobs, err := h.DispatchUseCase.ReplayDeadLetters()
+	if errors.Is(err, dispatch.ErrStopped) {
+		http.Error(w, "Failed to queue replays", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to replay dead letters", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Message string   `json:"message"`
+		JobIDs  []string `json:"job_ids"`
+	}{
+		Message: "Replays accepted",
+		JobIDs:  []string{},
+	}
+	for _, job := range jobs {
+		response.JobIDs = append(response.JobIDs, job.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,113 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDeadLetters_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/dead-letters", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	deadLetterMock.EXPECT().GetDeadLetters().Return([]entity.DeadLetter{getDeadLetter("dead")}, nil)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	var deadLetters []entity.DeadLetter
+	err := json.NewDecoder(got.Body).Decode(&deadLetters)
+	assert.NoError(t, err)
+	assert.Equal(t, "dead", deadLetters[0].ID)
+	controller.Finish()
+}
+
+func TestGetDeadLetter_NotFound_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/dead-letters/missing", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	deadLetterMock.EXPECT().GetDeadLetter("missing").Return(entity.DeadLetter{}, repositories.ErrDeadLetterNotFound)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	controller.Finish()
+}
+
+func TestReplayDeadLetter_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/dead-letters/dead/replay", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	deadLetterMock.EXPECT().GetDeadLetter("dead").Return(getDeadLetter("dead"), nil).Times(2)
+	deadLetterMock.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(deadLetter entity.DeadLetter) error {
+		assert.False(t, deadLetter.ReplayingSince.IsZero())
+		return nil
+	})
+	userMock.EXPECT().GetUser(1).Return(getUser(1), nil)
+	logMock.EXPECT().SaveLog(matchLog(getMessage(1, "SMS"))).Return(nil)
+	deadLetterMock.EXPECT().DeleteDeadLetter("dead").Return(nil)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+
+	job := waitForJob(t, getJobID(t, got))
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, "dead", job.DeadLetterID)
+	assert.Equal(t, 1, len(job.Logs))
+	controller.Finish()
+}
+
+func TestReplayDeadLetter_InProgress_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/dead-letters/dead/replay", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	deadLetter := getDeadLetter("dead")
+	deadLetter.ReplayingSince = time.Now()
+
+	deadLetterMock.EXPECT().GetDeadLetter("dead").Return(deadLetter, nil)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusConflict, got.StatusCode)
+	controller.Finish()
+}
+
+func TestReplayDeadLetters_Method_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/dead-letters/replay", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+	handler.ReplayDeadLetters(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusMethodNotAllowed, got.StatusCode)
+	controller.Finish()
+}
+
+func getDeadLetter(id string) entity.DeadLetter {
+	return entity.DeadLetter{
+		ID: id,
+		Notification: entity.Notification{
+			Message:  "Test Submit Notification",
+			Category: entity.SportsCategory,
+		},
+		UserID:    1,
+		Channel:   entity.SMSChannel,
+		Error:     "gateway unavailable",
+		Attempts:  3,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,147 @@
+package notification_handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"notification/internal/entity"
+	"notification/internal/usecase/idempotency"
+)
+
+const (
+	idempotencyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+)
+
+// storedHeaders are the response headers replayed with a stored response.
+var storedHeaders = []string{"Content-Type", "Location"}
+
+// idempotent lets clients retry a request safely with an Idempotency-Key
+// header. A retry with the same key and body gets the original response
+// without the request being handled again, and one with a different body is
+// a conflict. Server errors aren't stored, and neither is a request that
+// panicked, so the request can be retried. Keys are kept per caller.
+// Requests without the header, or without an IdempotencyUseCase, are
+// handled as usual.
+func (h *NotificationHandler) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyHeader)
+		if key == "" || h.IdempotencyUseCase == nil {
+			next(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLen {
+			http.Error(w, fmt.Sprintf("%s must be at most %d characters", idempotencyHeader, maxIdempotencyKeyLen), http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		caller := requestCaller(r)
+		stored, err := h.IdempotencyUseCase.Begin(caller, key, fingerprint(r, body))
+		switch {
+		case errors.Is(err, idempotency.ErrKeyMismatch), errors.Is(err, idempotency.ErrRequestInProgress):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, "Failed to check idempotency key", http.StatusInternalServerError)
+			return
+		case stored != nil:
+			for name, value := range stored.Header {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			io.WriteString(w, stored.Body)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		handled := false
+		defer func() {
+			if handled {
+				return
+			}
+			if err := h.IdempotencyUseCase.Release(caller, key); err != nil {
+				fmt.Printf("Failed to release idempotency key %s: %v\n", key, err)
+			}
+		}()
+
+		next(recorder, r)
+		handled = true
+
+		if recorder.statusCode >= http.StatusInternalServerError {
+			err = h.IdempotencyUseCase.Release(caller, key)
+		} else {
+			err = h.IdempotencyUseCase.Complete(caller, key, recorder.response())
+		}
+		if err != nil {
+			fmt.Printf("Failed to store idempotency key %s: %v\n", key, err)
+		}
+	}
+}
+
+// requestCaller identifies who sent a request: the JWT's subject prefixed
+// with "jwt:" or the API key's ID prefixed with "key:". It scopes idempotency
+// keys and is recorded as RequestedBy. It is empty when the server runs
+// without authentication.
+func requestCaller(r *http.Request) string {
+	if principal, ok := requestPrincipal(r); ok {
+		return "jwt:" + principal.Subject
+	}
+
+	if key, ok := requestKey(r); ok {
+		return "key:" + key.ID
+	}
+
+	return ""
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.Path)
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(content []byte) (int, error) {
+	r.body.Write(content)
+	return r.ResponseWriter.Write(content)
+}
+
+func (r *responseRecorder) response() entity.StoredResponse {
+	header := map[string]string{}
+	for _, name := range storedHeaders {
+		if value := r.Header().Get(name); value != "" {
+			header[name] = value
+		}
+	}
+
+	return entity.StoredResponse{
+		StatusCode: r.statusCode,
+		Header:     header,
+		Body:       r.body.String(),
+	}
+}
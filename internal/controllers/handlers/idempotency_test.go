@@ -0,0 +1,93 @@
+package notification_handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/idempotency"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmitNotification_Idempotency_Success(t *testing.T) {
+	setHandlerAndLogMock(t)
+	handler.IdempotencyUseCase = idempotency.NewIdempotencyUseCase(repositories.NewIdempotencyRepository(t.TempDir()+"/idempotency.json"), time.Hour)
+	router := handler.RegisterRoutes()
+
+	userMock.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+	logMock.EXPECT().SaveLog(matchLog(getMessage(1, "SMS"))).Return(nil)
+
+	body := `{"category": "Sports", "message": "Test Submit Notification"}`
+	first := submitWithKey(router, "retry-1", body)
+	assert.Equal(t, http.StatusAccepted, first.StatusCode)
+	jobID := getJobID(t, first)
+
+	retry := submitWithKey(router, "retry-1", body)
+	assert.Equal(t, http.StatusAccepted, retry.StatusCode)
+	assert.Equal(t, "true", retry.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, jobID, getJobID(t, retry))
+
+	conflict := submitWithKey(router, "retry-1", `{"category": "Sports", "message": "Another message"}`)
+	assert.Equal(t, http.StatusConflict, conflict.StatusCode)
+
+	job := waitForJob(t, jobID)
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	controller.Finish()
+}
+
+func TestSubmitNotification_Idempotency_Error(t *testing.T) {
+	setHandlerAndLogMock(t)
+	handler.IdempotencyUseCase = idempotency.NewIdempotencyUseCase(repositories.NewIdempotencyRepository(t.TempDir()+"/idempotency.json"), time.Hour)
+	router := handler.RegisterRoutes()
+
+	body := `{"category": "Cooking", "message": "Test Submit Notification"}`
+	assert.Equal(t, http.StatusBadRequest, submitWithKey(router, "retry-2", body).StatusCode)
+
+	retry := submitWithKey(router, "retry-2", body)
+	assert.Equal(t, http.StatusBadRequest, retry.StatusCode)
+	assert.Equal(t, "true", retry.Header.Get("Idempotent-Replayed"))
+
+	assert.Equal(t, http.StatusBadRequest, submitWithKey(router, strings.Repeat("k", 256), body).StatusCode)
+	controller.Finish()
+}
+
+func TestIdempotent_Panic_Success(t *testing.T) {
+	service := idempotency.NewIdempotencyUseCase(repositories.NewIdempotencyRepository(t.TempDir()+"/idempotency.json"), time.Hour)
+	panicking := &NotificationHandler{IdempotencyUseCase: service}
+	submit := panicking.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader("{}"))
+	r.Header.Set("Idempotency-Key", "retry-3")
+	assert.Panics(t, func() { submit(httptest.NewRecorder(), r) })
+
+	// The key was released, so the request can be retried at once.
+	response, err := service.Begin("", "retry-3", fingerprint(r, []byte("{}")))
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
+
+func TestRequestCaller_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/add", nil)
+	assert.Equal(t, "", requestCaller(r))
+
+	keyed := r.WithContext(context.WithValue(r.Context(), apiKeyKey{}, entity.APIKey{ID: "1"}))
+	assert.Equal(t, "key:1", requestCaller(keyed))
+
+	signed := r.WithContext(context.WithValue(r.Context(), principalKey{}, entity.Principal{Subject: "1"}))
+	assert.Equal(t, "jwt:1", requestCaller(signed))
+}
+
+func submitWithKey(router http.Handler, key string, body string) *http.Response {
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(body))
+	r.Header.Set("Idempotency-Key", key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w.Result()
+}
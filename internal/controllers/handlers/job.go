@@ -0,0 +1,90 @@
+package notification_handler
+
+import (
+	"notification/internal/entity"
+	"time"
+)
+
+// jobResponse encodes a job in snake_case. Its logs are encoded like the
+// ones /get returns.
+type jobResponse struct {
+	ID           string               `json:"id"`
+	Notification notificationResponse `json:"notification"`
+	DeadLetterID string               `json:"dead_letter_id,omitempty"`
+	Status       entity.JobStatus     `json:"status"`
+	Total        int                  `json:"total"`
+	Processed    int                  `json:"processed"`
+	Logs         []entity.Log         `json:"logs"`
+	Failed       []deliveryResponse   `json:"failed"`
+	Error        string               `json:"error,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+type notificationResponse struct {
+	ID          string                 `json:"id"`
+	Category    entity.Category        `json:"category"`
+	Message     string                 `json:"message,omitempty"`
+	Messages    map[string]string      `json:"messages,omitempty"`
+	TemplateID  string                 `json:"template_id,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	Priority    entity.Priority        `json:"priority,omitempty"`
+	RequestedBy string                 `json:"requested_by,omitempty"`
+}
+
+type deliveryResponse struct {
+	UserID           int            `json:"user_id"`
+	Channel          entity.Channel `json:"channel"`
+	NotificationType string         `json:"notification_type"`
+	Attempts         int            `json:"attempts"`
+	Error            string         `json:"error"`
+	DeadLetterID     string         `json:"dead_letter_id,omitempty"`
+}
+
+func newJobResponse(job entity.Job) jobResponse {
+	response := jobResponse{
+		ID:           job.ID,
+		Notification: newNotificationResponse(job.Notification),
+		DeadLetterID: job.DeadLetterID,
+		Status:       job.Status,
+		Total:        job.Total,
+		Processed:    job.Processed,
+		Logs:         job.Logs,
+		Failed:       make([]deliveryResponse, 0, len(job.Failed)),
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		UpdatedAt:    job.UpdatedAt,
+	}
+	if response.Logs == nil {
+		response.Logs = []entity.Log{}
+	}
+	for _, delivery := range job.Failed {
+		response.Failed = append(response.Failed, newDeliveryResponse(delivery))
+	}
+
+	return response
+}
+
+func newNotificationResponse(notification entity.Notification) notificationResponse {
+	return notificationResponse{
+		ID:          notification.ID,
+		Category:    notification.Category,
+		Message:     notification.Message,
+		Messages:    notification.Messages,
+		TemplateID:  notification.TemplateID,
+		Data:        notification.Data,
+		Priority:    notification.EffectivePriority(),
+		RequestedBy: notification.RequestedBy,
+	}
+}
+
+func newDeliveryResponse(delivery entity.Delivery) deliveryResponse {
+	return deliveryResponse{
+		UserID:           delivery.UserID,
+		Channel:          delivery.Channel,
+		NotificationType: delivery.NotificationType,
+		Attempts:         delivery.Attempts,
+		Error:            delivery.Error,
+		DeadLetterID:     delivery.DeadLetterID,
+	}
+}
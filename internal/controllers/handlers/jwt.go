@@ -0,0 +1,63 @@
+package notification_handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"notification/internal/entity"
+	"notification/internal/usecase/jwtauth"
+	"strings"
+)
+
+type principalKey struct{}
+
+type JWTHandler struct {
+	Verifier *jwtauth.Verifier
+}
+
+func NewJWTHandler(verifier *jwtauth.Verifier) *JWTHandler {
+	return &JWTHandler{
+		Verifier: verifier,
+	}
+}
+
+// Authenticate is a router middleware for requests with a JWT bearer token.
+// A valid token whose roles grant the scope of the matched route lets the
+// request through with the token's principal; other requests are left to
+// the API key middleware, which must follow it.
+func (h *JWTHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !ok || strings.Count(token, ".") != 2 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := h.Verifier.Verify(token)
+		if errors.Is(err, jwtauth.ErrInvalidToken) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="notifications", error="invalid_token"`)
+			writeJSONError(w, http.StatusUnauthorized, "unauthorized", "The bearer token is invalid or expired")
+			return
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "internal_error", "Failed to check bearer token")
+			return
+		}
+
+		if scope := requiredScope(r); !principal.HasScope(scope) {
+			writeJSONError(w, http.StatusForbidden, "forbidden", fmt.Sprintf("The token's roles lack the %s scope", scope))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+// requestPrincipal returns the verified principal of a request authenticated
+// with a JWT.
+func requestPrincipal(r *http.Request) (entity.Principal, bool) {
+	principal, ok := r.Context().Value(principalKey{}).(entity.Principal)
+	return principal, ok
+}
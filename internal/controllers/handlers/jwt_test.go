@@ -0,0 +1,145 @@
+package notification_handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"notification/internal/entity"
+	"notification/internal/usecase/jwtauth"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+const jwtSecret = "jwt-secret"
+
+func TestJWT_Submit_Success(t *testing.T) {
+	router := setJWTRouter(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "message": "Test Submit Notification"}`))
+	r.Header.Set("Authorization", "Bearer "+signToken(t, "billing-service", "sender"))
+	w := httptest.NewRecorder()
+
+	userMock.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+	message := getMessage(1, "SMS")
+	message.RequestedBy = "jwt:billing-service"
+	logMock.EXPECT().SaveLog(matchLog(message)).Return(nil)
+	router.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+
+	job := waitForJob(t, getJobID(t, got))
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, "jwt:billing-service", job.Logs[0].RequestedBy)
+	controller.Finish()
+}
+
+func TestAPIKey_Submit_Success(t *testing.T) {
+	router := setJWTRouter(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "message": "Test Submit Notification"}`))
+	r.Header.Set("X-API-Key", adminKey)
+	w := httptest.NewRecorder()
+
+	userMock.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+	message := getMessage(1, "SMS")
+	message.RequestedBy = "key:admin"
+	logMock.EXPECT().SaveLog(matchLog(message)).Return(nil)
+	router.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+
+	job := waitForJob(t, getJobID(t, got))
+	assert.Equal(t, "key:admin", job.Logs[0].RequestedBy)
+	controller.Finish()
+}
+
+func TestJWT_Roles_Error(t *testing.T) {
+	router := setJWTRouter(t)
+
+	r := httptest.NewRequest(http.MethodDelete, "/delete", nil)
+	r.Header.Set("Authorization", "Bearer "+signToken(t, "billing-service", "sender log_reader"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "forbidden", decodeAuthError(t, w))
+
+	r = httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set("Authorization", "Bearer "+signToken(t, "ops", "log_admin"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	r = httptest.NewRequest(http.MethodPost, "/webhooks/sms", strings.NewReader(`{"status": "delivered"}`))
+	r.Header.Set("Authorization", "Bearer "+signToken(t, "ops", "user_admin template_admin"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	controller.Finish()
+}
+
+func TestJWT_Roles_Success(t *testing.T) {
+	router := setJWTRouter(t)
+
+	// The request gets past authentication and fails validation.
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/sms", strings.NewReader(`{"status": "delivered"}`))
+	r.Header.Set("Authorization", "Bearer "+signToken(t, "sms-gateway", "receipt_writer"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	assert.ElementsMatch(t, []entity.Scope{entity.ScopeManageUsers, entity.ScopeManageTemplates},
+		append(entity.RoleUserAdmin.Scopes(), entity.RoleTemplateAdmin.Scopes()...))
+	controller.Finish()
+}
+
+func TestJWT_Invalid_Error(t *testing.T) {
+	router := setJWTRouter(t)
+
+	expired := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "billing-service",
+		"aud":   "notifications",
+		"roles": "sender",
+		"exp":   time.Now().Add(-time.Minute).Unix(),
+	})
+	signed, err := expired.SignedString([]byte(jwtSecret))
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{}`))
+	r.Header.Set("Authorization", "Bearer "+signed)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "unauthorized", decodeAuthError(t, w))
+
+	r = httptest.NewRequest(http.MethodGet, "/get", nil)
+	r.Header.Set("Authorization", "Bearer "+adminKey)
+	w = httptest.NewRecorder()
+	logMock.EXPECT().GetLogsPage(entity.LogFilter{}).Return([]entity.Log{}, 0, nil)
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	controller.Finish()
+}
+
+func setJWTRouter(t *testing.T) *mux.Router {
+	verifier, err := jwtauth.NewVerifier(jwtauth.Config{Secrets: []string{jwtSecret}, Audience: "notifications"})
+	assert.NoError(t, err)
+
+	return setAPIKeyRouter(t, NewJWTHandler(verifier).Authenticate)
+}
+
+func signToken(t *testing.T, subject string, roles string) string {
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   subject,
+		"aud":   "notifications",
+		"roles": roles,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(jwtSecret))
+	assert.NoError(t, err)
+	return signed
+}
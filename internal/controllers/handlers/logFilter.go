@@ -0,0 +1,140 @@
+package notification_handler
+
+import (
+	"fmt"
+	"net/url"
+	"notification/internal/entity"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLogLimit = 100
+	maxLogLimit     = 1000
+)
+
+// logFilter reads the GET /get query parameters. Time bounds are RFC 3339.
+// Without pagination parameters every matching log is returned; an offset
+// without a limit returns one page of defaultLogLimit logs.
+func logFilter(query url.Values) (entity.LogFilter, error) {
+	filter, err := logSelection(query)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Sort = entity.SortOrder(query.Get("sort"))
+	if !filter.Sort.IsValid() {
+		return filter, fmt.Errorf("Invalid sort, use asc or desc")
+	}
+
+	if filter.Offset, err = intParameter(query, "offset", 0); err != nil || filter.Offset < 0 {
+		return filter, fmt.Errorf("Invalid offset")
+	}
+
+	if !query.Has("offset") && !query.Has("limit") {
+		return filter, nil
+	}
+
+	if filter.Limit, err = intParameter(query, "limit", defaultLogLimit); err != nil || filter.Limit < 1 || filter.Limit > maxLogLimit {
+		return filter, fmt.Errorf("Invalid limit, use 1 to %d", maxLogLimit)
+	}
+
+	return filter, nil
+}
+
+// logSelectionParameters are the parameters read by logSelection.
+var logSelectionParameters = []string{"id", "notification_id", "user_id", "category", "type", "status", "q", "from", "to"}
+
+// deleteSelection reads the DELETE /delete query parameters. Unknown
+// parameters are rejected, so that a misspelled one doesn't widen the
+// selection, and deleting every log takes an explicit all=true.
+func deleteSelection(query url.Values) (entity.LogFilter, error) {
+	for name := range query {
+		if name != "all" && !containsParameter(logSelectionParameters, name) {
+			return entity.LogFilter{}, fmt.Errorf("Unknown parameter %s", name)
+		}
+	}
+
+	all := query.Get("all")
+	if all != "" && all != "true" {
+		return entity.LogFilter{}, fmt.Errorf("Invalid all, use true")
+	}
+
+	filter, err := logSelection(query)
+	if err != nil {
+		return filter, err
+	}
+
+	if all == "true" && filter != (entity.LogFilter{}) {
+		return filter, fmt.Errorf("Use either all=true or selection parameters")
+	}
+
+	if all != "true" && filter == (entity.LogFilter{}) {
+		return filter, fmt.Errorf("Select the logs to delete, or use all=true to delete every log")
+	}
+
+	return filter, nil
+}
+
+func containsParameter(parameters []string, name string) bool {
+	for _, parameter := range parameters {
+		if parameter == name {
+			return true
+		}
+	}
+	return false
+}
+
+// logSelection reads the parameters that select logs, shared by GET /get
+// and DELETE /delete. Without any of them every log is selected.
+func logSelection(query url.Values) (entity.LogFilter, error) {
+	filter := entity.LogFilter{
+		ID:               query.Get("id"),
+		NotificationID:   query.Get("notification_id"),
+		Category:         entity.Category(query.Get("category")),
+		NotificationType: query.Get("type"),
+		Status:           entity.LogStatus(query.Get("status")),
+		Search:           query.Get("q"),
+	}
+
+	if filter.Category != "" && !filter.Category.IsValid() {
+		return filter, fmt.Errorf("Invalid category")
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return filter, fmt.Errorf("Invalid status, use queued, sent, delivered, opened, bounced, deferred, failed, dropped or downgraded")
+	}
+
+	var err error
+	if filter.UserID, err = intParameter(query, "user_id", 0); err != nil || filter.UserID < 0 {
+		return filter, fmt.Errorf("Invalid user_id")
+	}
+
+	if filter.From, err = timeParameter(query, "from"); err != nil {
+		return filter, fmt.Errorf("Invalid from, use RFC 3339")
+	}
+
+	if filter.To, err = timeParameter(query, "to"); err != nil {
+		return filter, fmt.Errorf("Invalid to, use RFC 3339")
+	}
+
+	return filter, nil
+}
+
+func intParameter(query url.Values, name string, fallback int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func timeParameter(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
@@ -2,20 +2,45 @@ package notification_handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/dispatch"
+	"notification/internal/usecase/idempotency"
 	"notification/internal/usecase/notification"
+	"notification/internal/usecase/schedule"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 type NotificationHandler struct {
 	NotificationUseCase *notification.NotificationUseCase
+	DispatchUseCase     *dispatch.DispatchUseCase
+	ScheduleUseCase     *schedule.ScheduleUseCase
+	IdempotencyUseCase  *idempotency.IdempotencyUseCase
 }
 
-func NewNotificationHandler(notificationUseCase *notification.NotificationUseCase) *NotificationHandler {
+type notificationRequest struct {
+	Category   entity.Category        `json:"category"`
+	Message    string                 `json:"message"`
+	Messages   map[string]string      `json:"messages"`
+	TemplateID string                 `json:"template_id"`
+	Data       map[string]interface{} `json:"data"`
+	SendAt     time.Time              `json:"send_at"`
+	Cron       string                 `json:"cron"`
+	Priority   entity.Priority        `json:"priority"`
+	// Urgent is deprecated and stands for the critical priority.
+	Urgent bool `json:"urgent"`
+}
+
+func NewNotificationHandler(notificationUseCase *notification.NotificationUseCase, dispatchUseCase *dispatch.DispatchUseCase, scheduleUseCase *schedule.ScheduleUseCase) *NotificationHandler {
 	return &NotificationHandler{
 		NotificationUseCase: notificationUseCase,
+		DispatchUseCase:     dispatchUseCase,
+		ScheduleUseCase:     scheduleUseCase,
 	}
 }
 
@@ -25,53 +50,123 @@ func (h *NotificationHandler) SubmitNotification(w http.ResponseWriter, r *http.
 		return
 	}
 
-	var requestBody struct {
-		Category entity.Category `json:"category"`
-		Message  string          `json:"message"`
+	var requestBody notificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&requestBody)
-	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := requestBody.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	notification := entity.Notification{
-		Message:  requestBody.Message,
-		Category: requestBody.Category,
+	notification := requestNotification(r, requestBody)
+	if !requestBody.SendAt.IsZero() || requestBody.Cron != "" {
+		h.scheduleNotification(w, requestBody, notification)
+		return
 	}
 
-	logs, err := h.NotificationUseCase.SendNotification(notification)
+	err := h.NotificationUseCase.CheckNotification(notification)
+	if errors.Is(err, log.ErrTemplateNotFound) {
+		http.Error(w, "Template not found", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to send notification", http.StatusInternalServerError)
+		http.Error(w, "Failed to check template", http.StatusInternalServerError)
+		return
+	}
+
+	job, err := h.DispatchUseCase.Enqueue(notification)
+	if err != nil {
+		http.Error(w, "Failed to queue notification", http.StatusServiceUnavailable)
 		return
 	}
 
 	response := struct {
-		Message string       `json:"message"`
-		Logs    []entity.Log `json:"logs"`
+		Message        string `json:"message"`
+		JobID          string `json:"job_id"`
+		NotificationID string `json:"notification_id"`
 	}{
-		Message: "Notification sent successfully",
-		Logs:    logs,
+		Message:        "Notification accepted",
+		JobID:          job.ID,
+		NotificationID: job.Notification.ID,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(response)
 }
 
+// requestNotification returns the notification of the request body, sent on
+// behalf of the subject of the request's JWT or of its API key.
+func requestNotification(r *http.Request, requestBody notificationRequest) entity.Notification {
+	notification := requestBody.toNotification()
+	notification.RequestedBy = requestCaller(r)
+	return notification
+}
+
+func (h *NotificationHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job, err := h.DispatchUseCase.GetJob(mux.Vars(r)["id"])
+	if errors.Is(err, log.ErrJobNotFound) {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get job", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newJobResponse(job))
+}
+
+func (h *NotificationHandler) GetNotification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	deliveries, err := h.NotificationUseCase.GetNotification(mux.Vars(r)["id"])
+	if errors.Is(err, notification.ErrNotificationNotFound) {
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get notification", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
 func (h *NotificationHandler) GetLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	logs, err := h.NotificationUseCase.GetLogs()
+	filter, err := logFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logs, total, err := h.NotificationUseCase.GetLogs(filter)
 	if err != nil {
 		http.Error(w, "Failed to get logs", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	json.NewEncoder(w).Encode(logs)
 }
 
@@ -81,7 +176,13 @@ func (h *NotificationHandler) DeleteLogs(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.NotificationUseCase.DeleteLogs()
+	filter, err := deleteSelection(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := h.NotificationUseCase.DeleteLogs(filter)
 	if err != nil {
 		http.Error(w, "Failed on delete logs", http.StatusInternalServerError)
 		return
@@ -89,8 +190,10 @@ func (h *NotificationHandler) DeleteLogs(w http.ResponseWriter, r *http.Request)
 
 	response := struct {
 		Message string `json:"message"`
+		Deleted int    `json:"deleted"`
 	}{
 		Message: "Logs deleted",
+		Deleted: deleted,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -99,9 +202,65 @@ func (h *NotificationHandler) DeleteLogs(w http.ResponseWriter, r *http.Request)
 
 func (h *NotificationHandler) RegisterRoutes() *mux.Router {
 	router := mux.NewRouter()
-	router.HandleFunc("/add", h.SubmitNotification)
+	router.HandleFunc("/add", h.idempotent(h.SubmitNotification))
 	router.HandleFunc("/get", h.GetLogs)
 	router.HandleFunc("/delete", h.DeleteLogs)
+	router.HandleFunc("/jobs/{id}", h.GetJob)
+	router.HandleFunc("/notifications/{id}", h.GetNotification).Methods(http.MethodGet)
+	router.HandleFunc("/webhooks/{channel}", h.ReceiveReceipt).Methods(http.MethodPost)
+	router.HandleFunc("/dead-letters", h.GetDeadLetters)
+	router.HandleFunc("/dead-letters/replay", h.ReplayDeadLetters)
+	router.HandleFunc("/dead-letters/{id}", h.GetDeadLetter)
+	router.HandleFunc("/dead-letters/{id}/replay", h.ReplayDeadLetter)
+	router.HandleFunc("/schedules", h.GetSchedules).Methods(http.MethodGet)
+	router.HandleFunc("/schedules/{id}", h.GetSchedule).Methods(http.MethodGet)
+	router.HandleFunc("/schedules/{id}", h.UpdateSchedule).Methods(http.MethodPut)
+	router.HandleFunc("/schedules/{id}", h.CancelSchedule).Methods(http.MethodDelete)
 
 	return router
 }
+
+func (b notificationRequest) validate() error {
+	if !b.Category.IsValid() || !b.toNotification().HasContent() {
+		return errors.New("Invalid request body")
+	}
+
+	for locale, message := range b.Messages {
+		if !entity.IsValidLocale(locale) {
+			return errors.New("Invalid locale " + locale)
+		}
+		if message == "" {
+			return errors.New("Empty message for locale " + locale)
+		}
+	}
+
+	if !b.Priority.IsValid() {
+		return errors.New("Invalid priority, use low, normal, high or critical")
+	}
+	if b.Urgent && b.Priority != "" && b.Priority != entity.PriorityCritical {
+		return errors.New("urgent is deprecated and means priority critical, drop it to use another priority")
+	}
+
+	return nil
+}
+
+func (b notificationRequest) toNotification() entity.Notification {
+	return entity.Notification{
+		Message:    b.Message,
+		Messages:   b.Messages,
+		Category:   b.Category,
+		TemplateID: b.TemplateID,
+		Data:       b.Data,
+		Priority:   b.priority(),
+	}
+}
+
+// priority returns the requested priority, with the deprecated urgent flag
+// read as critical.
+func (b notificationRequest) priority() entity.Priority {
+	if b.Urgent {
+		return entity.PriorityCritical
+	}
+
+	return b.Priority
+}
@@ -7,22 +7,30 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/dispatch"
 	usecase "notification/internal/usecase/notification"
+	"notification/internal/usecase/schedule"
 	log "notification/test/platform"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 var (
-	handler     *NotificationHandler
-	logMock     *log.MockLog
-	controller  *gomock.Controller
-	usecaseMock *usecase.NotificationUseCase
-	anyError    = errors.New("Error")
+	handler        *NotificationHandler
+	logMock        *log.MockLog
+	userMock       *log.MockUser
+	deadLetterMock *log.MockDeadLetter
+	templateMock   *log.MockTemplate
+	controller     *gomock.Controller
+	usecaseMock    *usecase.NotificationUseCase
+	anyError       = errors.New("Error")
 )
 
 func (mock *MockHTTP) Do(_ *http.Request) (*http.Response, error) {
@@ -40,13 +48,21 @@ func TestSubmitNotification_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	setHandlerAndLogMock(t)
 
+	userMock.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
 	message := getMessage(1, "SMS")
-	logMock.EXPECT().SaveLog(message).Return(nil)
+	logMock.EXPECT().SaveLog(matchLog(message)).Return(nil)
 
 	handler.SubmitNotification(w, r)
 
 	got := w.Result()
-	assert.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+
+	job := waitForJob(t, getJobID(t, got))
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, 1, job.Processed)
+	assert.Equal(t, 1, len(job.Logs))
+	assert.True(t, isGeneratedID(job.Notification.ID))
+	assert.Equal(t, job.Notification.ID, job.Logs[0].NotificationID)
 	controller.Finish()
 }
 
@@ -68,13 +84,213 @@ func TestSubmitNotification_Error(t *testing.T) {
 	w := httptest.NewRecorder()
 	setHandlerAndLogMock(t)
 
+	userMock.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
 	message := getMessage(1, "SMS")
-	logMock.EXPECT().SaveLog(message).Return(anyError)
+	logMock.EXPECT().SaveLog(matchLog(message)).Return(anyError)
 
 	handler.SubmitNotification(w, r)
 
 	got := w.Result()
-	assert.Equal(t, http.StatusInternalServerError, got.StatusCode)
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+
+	job := waitForJob(t, getJobID(t, got))
+	assert.Equal(t, entity.JobFailed, job.Status)
+	assert.Equal(t, "Error", job.Error)
+	controller.Finish()
+}
+
+func TestSubmitNotification_Category_Error(t *testing.T) {
+	bodyReader := strings.NewReader(`{"category": "Cooking", "message": "Test Submit Notification"}`)
+	r := httptest.NewRequest(http.MethodPost, "/add", bodyReader)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+	handler.SubmitNotification(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusBadRequest, got.StatusCode)
+	controller.Finish()
+}
+
+func TestSubmitNotification_Template_Success(t *testing.T) {
+	bodyReader := strings.NewReader(`{"category": "Sports", "template_id": "goal", "data": {"team": "Lions"}}`)
+	r := httptest.NewRequest(http.MethodPost, "/add", bodyReader)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	template := entity.Template{ID: "goal", Body: "{{.Data.team}} scored, {{.User.Name}}"}
+	templateMock.EXPECT().GetTemplate("goal").Return(template, nil).Times(2)
+	userMock.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+	message := getMessage(1, "SMS")
+	message.Message = "Lions scored, Mary Alexander"
+	logMock.EXPECT().SaveLog(matchLog(message)).Return(nil)
+
+	handler.SubmitNotification(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+
+	job := waitForJob(t, getJobID(t, got))
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, "Lions", job.Notification.Data["team"])
+	controller.Finish()
+}
+
+func TestSubmitNotification_Messages_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "messages": {"pt": "Gol!", "en": "Goal!"}}`))
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	userMock.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+	logMock.EXPECT().SaveLog(gomock.Any()).Return(nil)
+
+	handler.SubmitNotification(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusAccepted, got.StatusCode)
+
+	job := waitForJob(t, getJobID(t, got))
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, "Goal!", job.Logs[0].Message)
+	controller.Finish()
+}
+
+func TestSubmitNotification_Template_Error(t *testing.T) {
+	setHandlerAndLogMock(t)
+
+	templateMock.EXPECT().GetTemplate("missing").Return(entity.Template{}, repositories.ErrTemplateNotFound)
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "template_id": "missing"}`))
+	w := httptest.NewRecorder()
+	handler.SubmitNotification(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	r = httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports"}`))
+	w = httptest.NewRecorder()
+	handler.SubmitNotification(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	r = httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "message": "Goal", "messages": {"Brazilian Portuguese": "Gol"}}`))
+	w = httptest.NewRecorder()
+	handler.SubmitNotification(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	r = httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "messages": {"pt": ""}}`))
+	w = httptest.NewRecorder()
+	handler.SubmitNotification(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	r = httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "message": "Goal", "priority": "urgent"}`))
+	w = httptest.NewRecorder()
+	handler.SubmitNotification(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	r = httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "message": "Goal", "priority": "low", "urgent": true}`))
+	w = httptest.NewRecorder()
+	handler.SubmitNotification(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	controller.Finish()
+}
+
+func TestGetJob_NotFound_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/jobs/unknown", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	controller.Finish()
+}
+
+func TestGetNotification_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/notifications/batch", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	message := getMessage(1, "SMS")
+	message.NotificationID = "batch"
+	logMock.EXPECT().GetLogs(entity.LogFilter{NotificationID: "batch", Sort: entity.SortAscending}).Return([]entity.Log{message}, nil)
+	deadLetterMock.EXPECT().GetDeadLetters().Return([]entity.DeadLetter{getDeadLetter("dead")}, nil)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	var deliveries entity.NotificationDeliveries
+	err := json.NewDecoder(got.Body).Decode(&deliveries)
+	assert.NoError(t, err)
+	assert.Equal(t, "batch", deliveries.ID)
+	assert.Equal(t, 1, len(deliveries.Logs))
+	assert.Equal(t, 0, len(deliveries.DeadLetters))
+	controller.Finish()
+}
+
+func TestGetNotification_NotFound_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/notifications/missing", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	logMock.EXPECT().GetLogs(entity.LogFilter{NotificationID: "missing", Sort: entity.SortAscending}).Return([]entity.Log{}, nil)
+	deadLetterMock.EXPECT().GetDeadLetters().Return([]entity.DeadLetter{}, nil)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	controller.Finish()
+}
+
+func TestScheduleNotification_Success(t *testing.T) {
+	sendAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	r := httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(`{"category": "Sports", "message": "Kick-off", "send_at": "`+sendAt+`"}`))
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+	router := handler.RegisterRoutes()
+	router.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusCreated, got.StatusCode)
+	var responseBody struct {
+		ScheduleID string `json:"schedule_id"`
+		NextRun    string `json:"next_run"`
+	}
+	assert.NoError(t, json.NewDecoder(got.Body).Decode(&responseBody))
+	assert.Equal(t, sendAt, responseBody.NextRun)
+	assert.Equal(t, "/schedules/"+responseBody.ScheduleID, got.Header.Get("Location"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schedules/"+responseBody.ScheduleID, nil))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var found entity.Schedule
+	assert.NoError(t, json.NewDecoder(w.Result().Body).Decode(&found))
+	assert.Equal(t, "Kick-off", found.Notification.Message)
+	assert.Equal(t, entity.ScheduleActive, found.Status)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/schedules/"+responseBody.ScheduleID, nil))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/schedules/"+responseBody.ScheduleID, nil))
+	assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+	controller.Finish()
+}
+
+func TestScheduleNotification_Error(t *testing.T) {
+	setHandlerAndLogMock(t)
+	router := handler.RegisterRoutes()
+
+	for _, body := range []string{
+		`{"category": "Sports", "message": "Kick-off", "cron": "every morning"}`,
+		`{"category": "Sports", "message": "Kick-off", "send_at": "2020-01-01T00:00:00Z"}`,
+		`{"category": "Sports", "message": "Kick-off", "send_at": "2020-01-01T00:00:00Z", "cron": "@daily"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/add", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, body)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/schedules/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
 	controller.Finish()
 }
 
@@ -98,7 +314,7 @@ func TestGetLogsAndDeleteLogs_Success(t *testing.T) {
 
 	// GET
 	message := getMessage(1, "SMS")
-	logMock.EXPECT().GetLogs().Return([]entity.Log{message}, nil)
+	logMock.EXPECT().GetLogsPage(gomock.Any()).Return([]entity.Log{message}, 1, nil)
 	handler.GetLogs(w, r)
 
 	got := w.Result()
@@ -112,10 +328,10 @@ func TestGetLogsAndDeleteLogs_Success(t *testing.T) {
 	assert.Equal(t, requestBody[0].Message, "Test Submit Notification")
 
 	// DELETE
-	r = httptest.NewRequest(http.MethodDelete, "/delete", nil)
+	r = httptest.NewRequest(http.MethodDelete, "/delete?all=true", nil)
 	w = httptest.NewRecorder()
 
-	logMock.EXPECT().DeleteLogs().Return(nil)
+	logMock.EXPECT().DeleteLogs(entity.LogFilter{}).Return(1, nil)
 	handler.DeleteLogs(w, r)
 
 	got = w.Result()
@@ -123,6 +339,60 @@ func TestGetLogsAndDeleteLogs_Success(t *testing.T) {
 	controller.Finish()
 }
 
+func TestGetLogs_Filter_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/get?user_id=1&category=Sports&type=SMS&from=2024-01-01T00:00:00Z&q=goal&sort=asc&offset=20&limit=10", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	filter := entity.LogFilter{
+		UserID:           1,
+		Category:         entity.SportsCategory,
+		NotificationType: "SMS",
+		From:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Search:           "goal",
+		Sort:             entity.SortAscending,
+		Offset:           20,
+		Limit:            10,
+	}
+	logMock.EXPECT().GetLogsPage(filter).Return([]entity.Log{getMessage(1, "SMS")}, 21, nil)
+	handler.GetLogs(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+	assert.Equal(t, "21", got.Header.Get("X-Total-Count"))
+}
+
+func TestGetLogs_Pagination_Success(t *testing.T) {
+	setHandlerAndLogMock(t)
+
+	for query, filter := range map[string]entity.LogFilter{
+		"/get":          {},
+		"/get?offset=5": {Offset: 5, Limit: defaultLogLimit},
+		"/get?limit=20": {Limit: 20},
+	} {
+		r := httptest.NewRequest(http.MethodGet, query, nil)
+		w := httptest.NewRecorder()
+
+		logMock.EXPECT().GetLogsPage(filter).Return([]entity.Log{}, 0, nil)
+		handler.GetLogs(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code, query)
+	}
+	controller.Finish()
+}
+
+func TestGetLogs_Filter_Error(t *testing.T) {
+	setHandlerAndLogMock(t)
+
+	for _, query := range []string{"user_id=abc", "category=Music", "sort=up", "limit=0", "limit=5000", "offset=-1", "from=yesterday"} {
+		r := httptest.NewRequest(http.MethodGet, "/get?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.GetLogs(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+	}
+}
+
 func TestGetLogs_Method_Error(t *testing.T) {
 	r := httptest.NewRequest(http.MethodDelete, "/get", nil)
 	w := httptest.NewRecorder()
@@ -143,7 +413,7 @@ func TestGetLogs_Error(t *testing.T) {
 
 	// GET
 	message := getMessage(1, "SMS")
-	logMock.EXPECT().GetLogs().Return([]entity.Log{message}, anyError)
+	logMock.EXPECT().GetLogsPage(gomock.Any()).Return([]entity.Log{message}, 0, anyError)
 	handler.GetLogs(w, r)
 
 	got := w.Result()
@@ -151,6 +421,47 @@ func TestGetLogs_Error(t *testing.T) {
 
 }
 
+func TestDeleteLogs_Filter_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodDelete, "/delete?user_id=2&category=Movies&to=2024-01-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	filter := entity.LogFilter{
+		UserID:   2,
+		Category: entity.MoviesCategory,
+		To:       time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	logMock.EXPECT().DeleteLogs(filter).Return(4, nil)
+	handler.DeleteLogs(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	var response struct {
+		Deleted int `json:"deleted"`
+	}
+	assert.NoError(t, json.NewDecoder(got.Body).Decode(&response))
+	assert.Equal(t, 4, response.Deleted)
+
+	r = httptest.NewRequest(http.MethodDelete, "/delete?to=last-week", nil)
+	w = httptest.NewRecorder()
+	handler.DeleteLogs(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func TestDeleteLogs_Selection_Error(t *testing.T) {
+	setHandlerAndLogMock(t)
+
+	for _, query := range []string{"", "?user=2", "?user_id=2&categroy=Movies", "?all=yes", "?all=true&user_id=2", "?id="} {
+		r := httptest.NewRequest(http.MethodDelete, "/delete"+query, nil)
+		w := httptest.NewRecorder()
+		handler.DeleteLogs(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	controller.Finish()
+}
+
 func TestDeleteLogs_Method_Error(t *testing.T) {
 	r := httptest.NewRequest(http.MethodGet, "/delete", nil)
 	w := httptest.NewRecorder()
@@ -171,7 +482,7 @@ func TestDeleteLogs_Error(t *testing.T) {
 
 	// GET
 	message := getMessage(1, "SMS")
-	logMock.EXPECT().GetLogs().Return([]entity.Log{message}, nil)
+	logMock.EXPECT().GetLogsPage(gomock.Any()).Return([]entity.Log{message}, 1, nil)
 	handler.GetLogs(w, r)
 
 	got := w.Result()
@@ -185,10 +496,10 @@ func TestDeleteLogs_Error(t *testing.T) {
 	assert.Equal(t, requestBody[0].Message, "Test Submit Notification")
 
 	// DELETE
-	r = httptest.NewRequest(http.MethodDelete, "/delete", nil)
+	r = httptest.NewRequest(http.MethodDelete, "/delete?all=true", nil)
 	w = httptest.NewRecorder()
 
-	logMock.EXPECT().DeleteLogs().Return(anyError)
+	logMock.EXPECT().DeleteLogs(gomock.Any()).Return(0, anyError)
 	handler.DeleteLogs(w, r)
 
 	got = w.Result()
@@ -199,17 +510,114 @@ func TestDeleteLogs_Error(t *testing.T) {
 func setHandlerAndLogMock(t *testing.T) {
 	controller = gomock.NewController(t)
 	logMock = log.NewMockLog(controller)
-	usecaseMock = usecase.NewNotificationUseCase(logMock)
-	handler = NewNotificationHandler(usecaseMock)
+	userMock = log.NewMockUser(controller)
+	deadLetterMock = log.NewMockDeadLetter(controller)
+	templateMock = log.NewMockTemplate(controller)
+	usecaseMock = usecase.NewNotificationUseCase(logMock, userMock, deadLetterMock)
+	usecaseMock.TemplateRepository = templateMock
+	dispatchUseCase := dispatch.NewDispatchUseCase(usecaseMock, repositories.NewJobRepository(""), 1)
+	dispatchUseCase.Start()
+	t.Cleanup(dispatchUseCase.Stop)
+	scheduleUseCase := schedule.NewScheduleUseCase(repositories.NewScheduleRepository(t.TempDir()+"/schedules.json"), usecaseMock, time.Second)
+	handler = NewNotificationHandler(usecaseMock, dispatchUseCase, scheduleUseCase)
+}
+
+func getJobID(t *testing.T, response *http.Response) string {
+	var responseBody struct {
+		JobID string `json:"job_id"`
+	}
+	err := json.NewDecoder(response.Body).Decode(&responseBody)
+	assert.NoError(t, err)
+	assert.Equal(t, "/jobs/"+responseBody.JobID, response.Header.Get("Location"))
+
+	return responseBody.JobID
+}
+
+func waitForJob(t *testing.T, id string) jobResponse {
+	router := handler.RegisterRoutes()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/jobs/"+id, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var job jobResponse
+		err := json.NewDecoder(w.Body).Decode(&job)
+		assert.NoError(t, err)
+		if job.Status == entity.JobCompleted || job.Status == entity.JobFailed {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s did not finish", id)
+	return jobResponse{}
+}
+
+func getUser(id int) entity.User {
+	return entity.User{
+		ID:          id,
+		Name:        "Mary Alexander",
+		Email:       "mary.alexander@outlook.com",
+		PhoneNumber: "78958745",
+		Subscribed:  []entity.Category{entity.SportsCategory},
+		Channels:    []entity.Channel{"SMS"},
+	}
 }
 
 func getMessage(id int, NotificationType string) entity.Log {
 	return entity.Log{
-		ID:               fmt.Sprintf("%v-%s-%s", id, string(entity.SportsCategory), string(NotificationType)),
 		UserID:           id,
 		Message:          "Test Submit Notification",
 		Category:         entity.SportsCategory,
 		NotificationType: NotificationType,
+		Locale:           entity.DefaultLocale,
+		Status:           entity.LogSent,
 		Timestamp:        time.Now(),
 	}
 }
+
+type logMatcher struct {
+	log entity.Log
+}
+
+func matchLog(log entity.Log) gomock.Matcher {
+	return logMatcher{log: log}
+}
+
+func (m logMatcher) Matches(x interface{}) bool {
+	log, ok := x.(entity.Log)
+	if !ok || log.Timestamp.Sub(m.log.Timestamp).Abs() > time.Second {
+		return false
+	}
+
+	// IDs are generated, so only their form is checked unless the expected
+	// log sets them.
+	if !isGeneratedID(log.ID) || (m.log.NotificationID == "" && !isGeneratedID(log.NotificationID)) {
+		return false
+	}
+	if m.log.ID == "" {
+		log.ID = ""
+	}
+	if m.log.NotificationID == "" {
+		log.NotificationID = ""
+	}
+
+	// Logs of queued notifications start their history when the job was
+	// queued, which the expected log leaves out.
+	if m.log.History == nil && len(log.History) == 2 && log.History[0].Status == entity.LogQueued && log.History[1].Status == log.Status {
+		log.History = nil
+	}
+
+	log.Timestamp = m.log.Timestamp
+	return reflect.DeepEqual(log, m.log)
+}
+
+func isGeneratedID(id string) bool {
+	parsed, err := uuid.Parse(id)
+	return err == nil && parsed.Version() == 7
+}
+
+func (m logMatcher) String() string {
+	return fmt.Sprintf("matches %v", m.log)
+}
@@ -0,0 +1,154 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// receiptRequest is the body providers post to /webhooks/{channel}, as JSON
+// or as a form. LogID is the ID of the delivery's log, which the notifiers
+// pass to the providers, and Timestamp, when given, is RFC 3339.
+type receiptRequest struct {
+	LogID     string    `json:"log_id"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var receiptChannels = map[string]entity.Channel{
+	"sms":   entity.SMSChannel,
+	"email": entity.EmailChannel,
+	"push":  entity.PushChannel,
+}
+
+// receiptStatuses maps the statuses each channel's providers report onto
+// delivery statuses, so Twilio- and SendGrid-style names are understood
+// alongside the log statuses themselves.
+var receiptStatuses = map[entity.Channel]map[string]entity.LogStatus{
+	entity.SMSChannel: {
+		"accepted":    entity.LogQueued,
+		"queued":      entity.LogQueued,
+		"sending":     entity.LogQueued,
+		"sent":        entity.LogSent,
+		"delivered":   entity.LogDelivered,
+		"read":        entity.LogOpened,
+		"undelivered": entity.LogFailed,
+		"failed":      entity.LogFailed,
+	},
+	entity.EmailChannel: {
+		"processed": entity.LogQueued,
+		"queued":    entity.LogQueued,
+		"sent":      entity.LogSent,
+		"delivered": entity.LogDelivered,
+		"open":      entity.LogOpened,
+		"opened":    entity.LogOpened,
+		"bounce":    entity.LogBounced,
+		"bounced":   entity.LogBounced,
+		"dropped":   entity.LogFailed,
+		"failed":    entity.LogFailed,
+	},
+	entity.PushChannel: {
+		"queued":    entity.LogQueued,
+		"sent":      entity.LogSent,
+		"delivered": entity.LogDelivered,
+		"received":  entity.LogDelivered,
+		"opened":    entity.LogOpened,
+		"failed":    entity.LogFailed,
+	},
+}
+
+// ReceiveReceipt records a provider's delivery receipt on the log it refers
+// to and answers with the log. Receipts that arrive out of order are
+// acknowledged without changing the log, so providers don't retry them.
+func (h *NotificationHandler) ReceiveReceipt(w http.ResponseWriter, r *http.Request) {
+	channel, ok := receiptChannels[mux.Vars(r)["channel"]]
+	if !ok {
+		http.Error(w, "Unknown channel, use sms, email or push", http.StatusNotFound)
+		return
+	}
+
+	requestBody, err := decodeReceipt(r)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.LogID == "" {
+		http.Error(w, "log_id is required", http.StatusBadRequest)
+		return
+	}
+
+	status, ok := receiptStatuses[channel][requestBody.Status]
+	if !ok {
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.NotificationUseCase.RecordReceipt(channel, entity.Receipt{
+		LogID:     requestBody.LogID,
+		Status:    status,
+		Error:     requestBody.Error,
+		Timestamp: requestBody.Timestamp,
+	})
+	if errors.Is(err, log.ErrLogNotFound) {
+		http.Error(w, "Log not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to record receipt", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// decodeReceipt reads a JSON receipt, or a form such as the status callbacks
+// of Twilio-style SMS gateways, which name the fields MessageStatus and
+// ErrorCode. The log_id query parameter, which the SMS status callback URL
+// carries, is used when the body has none.
+func decodeReceipt(r *http.Request) (receiptRequest, error) {
+	var receipt receiptRequest
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			return receipt, err
+		}
+
+		receipt.LogID = r.PostForm.Get("log_id")
+		receipt.Status = firstValue(r.PostForm, "status", "MessageStatus")
+		receipt.Error = firstValue(r.PostForm, "error", "ErrorMessage", "ErrorCode")
+		if timestamp := r.PostForm.Get("timestamp"); timestamp != "" {
+			parsed, err := time.Parse(time.RFC3339, timestamp)
+			if err != nil {
+				return receipt, err
+			}
+			receipt.Timestamp = parsed
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
+		return receipt, err
+	}
+
+	if receipt.LogID == "" {
+		receipt.LogID = r.URL.Query().Get("log_id")
+	}
+
+	return receipt, nil
+}
+
+func firstValue(values url.Values, keys ...string) string {
+	for _, key := range keys {
+		if value := values.Get(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification/internal/entity"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiveReceipt_Success(t *testing.T) {
+	bodyReader := strings.NewReader(`{"log_id": "log", "status": "bounce", "error": "mailbox full", "timestamp": "2024-06-03T10:00:00Z"}`)
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/email", bodyReader)
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	sent := getMessage(1, "E-Mail")
+	sent.ID = "log"
+	logMock.EXPECT().GetLogs(entity.LogFilter{ID: "log", NotificationType: "E-Mail"}).Return([]entity.Log{sent}, nil)
+	logMock.EXPECT().UpdateLog(gomock.Any()).Return(nil)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	var updated entity.Log
+	err := json.NewDecoder(got.Body).Decode(&updated)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.LogBounced, updated.Status)
+	assert.Equal(t, "mailbox full", updated.Error)
+	assert.Equal(t, 2, len(updated.History))
+	controller.Finish()
+}
+
+func TestReceiveReceipt_Callback_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/sms?log_id=log", strings.NewReader("MessageSid=SM1&MessageStatus=undelivered&ErrorCode=30003"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	setHandlerAndLogMock(t)
+
+	sent := getMessage(1, "SMS")
+	sent.ID = "log"
+	logMock.EXPECT().GetLogs(entity.LogFilter{ID: "log", NotificationType: "SMS"}).Return([]entity.Log{sent}, nil)
+	logMock.EXPECT().UpdateLog(gomock.Any()).Return(nil)
+	handler.RegisterRoutes().ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	var updated entity.Log
+	err := json.NewDecoder(got.Body).Decode(&updated)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.LogFailed, updated.Status)
+	assert.Equal(t, "30003", updated.Error)
+	controller.Finish()
+}
+
+func TestReceiveReceipt_Error(t *testing.T) {
+	setHandlerAndLogMock(t)
+	router := handler.RegisterRoutes()
+
+	requests := []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{"/webhooks/fax", `{"log_id": "log", "status": "delivered"}`, http.StatusNotFound},
+		{"/webhooks/sms", `{"log_id": "log", "status": "bounce"}`, http.StatusBadRequest},
+		{"/webhooks/email", `{"status": "delivered"}`, http.StatusBadRequest},
+	}
+	for _, request := range requests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, request.path, strings.NewReader(request.body)))
+		assert.Equal(t, request.status, w.Code, request.path)
+	}
+
+	logMock.EXPECT().GetLogs(entity.LogFilter{ID: "missing", NotificationType: "Push Notification"}).Return([]entity.Log{}, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/webhooks/push", strings.NewReader(`{"log_id": "missing", "status": "received"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	controller.Finish()
+}
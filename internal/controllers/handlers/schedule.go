@@ -0,0 +1,115 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/schedule"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *NotificationHandler) GetSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.ScheduleUseCase.GetSchedules()
+	if err != nil {
+		http.Error(w, "Failed to get schedules", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(schedules)
+}
+
+func (h *NotificationHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	found, err := h.ScheduleUseCase.GetSchedule(mux.Vars(r)["id"])
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(found)
+}
+
+func (h *NotificationHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	var requestBody notificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := requestBody.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.ScheduleUseCase.UpdateSchedule(entity.Schedule{
+		ID:           mux.Vars(r)["id"],
+		Notification: requestNotification(r, requestBody),
+		SendAt:       requestBody.SendAt,
+		Cron:         requestBody.Cron,
+	})
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (h *NotificationHandler) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	cancelled, err := h.ScheduleUseCase.CancelSchedule(mux.Vars(r)["id"])
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cancelled)
+}
+
+func (h *NotificationHandler) scheduleNotification(w http.ResponseWriter, requestBody notificationRequest, notification entity.Notification) {
+	created, err := h.ScheduleUseCase.CreateSchedule(entity.Schedule{
+		Notification: notification,
+		SendAt:       requestBody.SendAt,
+		Cron:         requestBody.Cron,
+	})
+	if err != nil {
+		writeScheduleError(w, err)
+		return
+	}
+
+	response := struct {
+		Message    string `json:"message"`
+		ScheduleID string `json:"schedule_id"`
+		NextRun    string `json:"next_run"`
+	}{
+		Message:    "Notification scheduled",
+		ScheduleID: created.ID,
+		NextRun:    created.NextRun.Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/schedules/"+created.ID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeScheduleError(w http.ResponseWriter, err error) {
+	var validationError schedule.ValidationError
+
+	switch {
+	case errors.As(err, &validationError):
+		http.Error(w, validationError.Message, http.StatusBadRequest)
+	case errors.Is(err, log.ErrScheduleNotFound):
+		http.Error(w, "Schedule not found", http.StatusNotFound)
+	case errors.Is(err, schedule.ErrScheduleNotActive):
+		http.Error(w, "Schedule is no longer active", http.StatusConflict)
+	default:
+		http.Error(w, "Failed to update schedules", http.StatusInternalServerError)
+	}
+}
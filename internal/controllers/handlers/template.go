@@ -0,0 +1,235 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/template"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type TemplateHandler struct {
+	TemplateUseCase *template.TemplateUseCase
+}
+
+type templateRequest struct {
+	ID           string                         `json:"id"`
+	Locale       string                         `json:"locale"`
+	Body         string                         `json:"body"`
+	SMS          smsTemplate                    `json:"sms"`
+	Email        emailTemplate                  `json:"email"`
+	Push         pushTemplate                   `json:"push"`
+	Translations map[string]templateTranslation `json:"translations"`
+}
+
+// templateResponse encodes a template in snake_case.
+type templateResponse struct {
+	ID           string                         `json:"id"`
+	Locale       string                         `json:"locale,omitempty"`
+	Body         string                         `json:"body"`
+	SMS          smsTemplate                    `json:"sms"`
+	Email        emailTemplate                  `json:"email"`
+	Push         pushTemplate                   `json:"push"`
+	Translations map[string]templateTranslation `json:"translations,omitempty"`
+	CreatedAt    time.Time                      `json:"created_at"`
+	UpdatedAt    time.Time                      `json:"updated_at"`
+}
+
+type templateTranslation struct {
+	Body  string        `json:"body"`
+	SMS   smsTemplate   `json:"sms"`
+	Email emailTemplate `json:"email"`
+	Push  pushTemplate  `json:"push"`
+}
+
+type smsTemplate struct {
+	Body string `json:"body"`
+}
+
+type emailTemplate struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	HTML    string `json:"html"`
+}
+
+type pushTemplate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func NewTemplateHandler(templateUseCase *template.TemplateUseCase) *TemplateHandler {
+	return &TemplateHandler{
+		TemplateUseCase: templateUseCase,
+	}
+}
+
+func (h *TemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	requestBody, err := decodeTemplateRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.TemplateUseCase.CreateTemplate(requestBody.toTemplate(requestBody.ID))
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newTemplateResponse(created))
+}
+
+func (h *TemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.TemplateUseCase.GetTemplates()
+	if err != nil {
+		http.Error(w, "Failed to get templates", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]templateResponse, 0, len(templates))
+	for _, found := range templates {
+		response = append(response, newTemplateResponse(found))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	found, err := h.TemplateUseCase.GetTemplate(mux.Vars(r)["id"])
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newTemplateResponse(found))
+}
+
+func (h *TemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	requestBody, err := decodeTemplateRequest(r)
+	if err != nil || (requestBody.ID != "" && requestBody.ID != id) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.TemplateUseCase.UpdateTemplate(requestBody.toTemplate(id))
+	if err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newTemplateResponse(updated))
+}
+
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	if err := h.TemplateUseCase.DeleteTemplate(mux.Vars(r)["id"]); err != nil {
+		writeTemplateError(w, err)
+		return
+	}
+
+	response := struct {
+		Message string `json:"message"`
+	}{
+		Message: "Template deleted",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *TemplateHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/templates", h.GetTemplates).Methods(http.MethodGet)
+	router.HandleFunc("/templates", h.CreateTemplate).Methods(http.MethodPost)
+	router.HandleFunc("/templates/{id}", h.GetTemplate).Methods(http.MethodGet)
+	router.HandleFunc("/templates/{id}", h.UpdateTemplate).Methods(http.MethodPut)
+	router.HandleFunc("/templates/{id}", h.DeleteTemplate).Methods(http.MethodDelete)
+}
+
+func (b templateRequest) toTemplate(id string) entity.Template {
+	var translations map[string]entity.TemplateTranslation
+	if b.Translations != nil {
+		translations = map[string]entity.TemplateTranslation{}
+		for locale, translation := range b.Translations {
+			translations[locale] = translation.toTranslation()
+		}
+	}
+
+	return entity.Template{
+		ID:           id,
+		Locale:       b.Locale,
+		Body:         b.Body,
+		SMS:          entity.SMSTemplate(b.SMS),
+		Email:        entity.EmailTemplate(b.Email),
+		Push:         entity.PushTemplate(b.Push),
+		Translations: translations,
+	}
+}
+
+func (t templateTranslation) toTranslation() entity.TemplateTranslation {
+	return entity.TemplateTranslation{
+		Body:  t.Body,
+		SMS:   entity.SMSTemplate(t.SMS),
+		Email: entity.EmailTemplate(t.Email),
+		Push:  entity.PushTemplate(t.Push),
+	}
+}
+
+func newTemplateResponse(found entity.Template) templateResponse {
+	response := templateResponse{
+		ID:        found.ID,
+		Locale:    found.Locale,
+		Body:      found.Body,
+		SMS:       smsTemplate(found.SMS),
+		Email:     emailTemplate(found.Email),
+		Push:      pushTemplate(found.Push),
+		CreatedAt: found.CreatedAt,
+		UpdatedAt: found.UpdatedAt,
+	}
+	if len(found.Translations) > 0 {
+		response.Translations = map[string]templateTranslation{}
+		for locale, translation := range found.Translations {
+			response.Translations[locale] = templateTranslation{
+				Body:  translation.Body,
+				SMS:   smsTemplate(translation.SMS),
+				Email: emailTemplate(translation.Email),
+				Push:  pushTemplate(translation.Push),
+			}
+		}
+	}
+
+	return response
+}
+
+func decodeTemplateRequest(r *http.Request) (templateRequest, error) {
+	var requestBody templateRequest
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&requestBody)
+	return requestBody, err
+}
+
+func writeTemplateError(w http.ResponseWriter, err error) {
+	var validationError template.ValidationError
+
+	switch {
+	case errors.As(err, &validationError):
+		http.Error(w, validationError.Message, http.StatusBadRequest)
+	case errors.Is(err, log.ErrTemplateNotFound):
+		http.Error(w, "Template not found", http.StatusNotFound)
+	case errors.Is(err, template.ErrTemplateAlreadyExists):
+		http.Error(w, "Template already exists", http.StatusConflict)
+	default:
+		http.Error(w, "Failed to update templates", http.StatusInternalServerError)
+	}
+}
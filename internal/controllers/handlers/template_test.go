@@ -0,0 +1,77 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/template"
+	log "notification/test/platform"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+var templateRouter *mux.Router
+
+func TestCreateTemplate_Success(t *testing.T) {
+	bodyReader := strings.NewReader(`{"id": "goal", "body": "{{.Data.team}} scored", "email": {"subject": "Goal!", "html": "<b>{{.Data.team}}</b>"}}`)
+	r := httptest.NewRequest(http.MethodPost, "/templates", bodyReader)
+	w := httptest.NewRecorder()
+	setTemplateHandlerAndMock(t)
+
+	templateMock.EXPECT().GetTemplate("goal").Return(entity.Template{}, repositories.ErrTemplateNotFound)
+	templateMock.EXPECT().SaveTemplate(gomock.Any()).Return(nil)
+	templateRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusCreated, got.StatusCode)
+
+	var created map[string]interface{}
+	err := json.NewDecoder(got.Body).Decode(&created)
+	assert.NoError(t, err)
+	assert.Equal(t, "goal", created["id"])
+	assert.Equal(t, "Goal!", created["email"].(map[string]interface{})["subject"])
+	assert.Contains(t, created, "created_at")
+	controller.Finish()
+}
+
+func TestCreateTemplate_Error(t *testing.T) {
+	setTemplateHandlerAndMock(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/templates", strings.NewReader(`{"id": "goal", "body": "{{.Data.team"}`))
+	w := httptest.NewRecorder()
+	templateRouter.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	templateMock.EXPECT().GetTemplate("goal").Return(entity.Template{ID: "goal"}, nil)
+	r = httptest.NewRequest(http.MethodPost, "/templates", strings.NewReader(`{"id": "goal", "body": "Goal"}`))
+	w = httptest.NewRecorder()
+	templateRouter.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+	controller.Finish()
+}
+
+func TestGetTemplate_NotFound_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/templates/goal", nil)
+	w := httptest.NewRecorder()
+	setTemplateHandlerAndMock(t)
+
+	templateMock.EXPECT().GetTemplate("goal").Return(entity.Template{}, repositories.ErrTemplateNotFound)
+	templateRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	controller.Finish()
+}
+
+func setTemplateHandlerAndMock(t *testing.T) {
+	controller = gomock.NewController(t)
+	templateMock = log.NewMockTemplate(controller)
+	templateRouter = mux.NewRouter()
+	NewTemplateHandler(template.NewTemplateUseCase(templateMock)).RegisterRoutes(templateRouter)
+}
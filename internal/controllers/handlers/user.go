@@ -0,0 +1,383 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/user"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type UserHandler struct {
+	UserUseCase *user.UserUseCase
+}
+
+type userRequest struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Email       string            `json:"email"`
+	PhoneNumber string            `json:"phone_number"`
+	Locale      string            `json:"locale"`
+	Timezone    string            `json:"timezone"`
+	QuietHours  *quietHours       `json:"quiet_hours"`
+	Subscribed  []entity.Category `json:"subscribed"`
+	Channels    []entity.Channel  `json:"channels"`
+	Fallback    []entity.Channel  `json:"fallback"`
+}
+
+// userResponse and deviceResponse encode users and devices with the same
+// snake_case names the requests use.
+type userResponse struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Email       string            `json:"email"`
+	PhoneNumber string            `json:"phone_number"`
+	Locale      string            `json:"locale,omitempty"`
+	Timezone    string            `json:"timezone,omitempty"`
+	QuietHours  *quietHours       `json:"quiet_hours,omitempty"`
+	Subscribed  []entity.Category `json:"subscribed"`
+	Channels    []entity.Channel  `json:"channels"`
+	Fallback    []entity.Channel  `json:"fallback,omitempty"`
+}
+
+type deviceResponse struct {
+	Token    string          `json:"token"`
+	UserID   int             `json:"user_id"`
+	Platform entity.Platform `json:"platform"`
+	LastSeen time.Time       `json:"last_seen"`
+}
+
+type quietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type deviceRequest struct {
+	Token    string          `json:"token"`
+	Platform entity.Platform `json:"platform"`
+}
+
+func NewUserHandler(userUseCase *user.UserUseCase) *UserHandler {
+	return &UserHandler{
+		UserUseCase: userUseCase,
+	}
+}
+
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	requestBody, err := decodeUserRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.UserUseCase.CreateUser(requestBody.toUser(requestBody.ID))
+	if err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newUserResponse(created))
+}
+
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.UserUseCase.GetUsers()
+	if err != nil {
+		http.Error(w, "Failed to get users", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]userResponse, 0, len(users))
+	for _, user := range users {
+		response = append(response, newUserResponse(user))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	found, err := h.UserUseCase.GetUser(id)
+	if err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newUserResponse(found))
+}
+
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	requestBody, err := decodeUserRequest(r)
+	if err != nil || (requestBody.ID != 0 && requestBody.ID != id) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated := requestBody.toUser(id)
+	if err := h.UserUseCase.UpdateUser(updated); err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newUserResponse(updated))
+}
+
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.UserUseCase.DeleteUser(id); err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	response := struct {
+		Message string `json:"message"`
+	}{
+		Message: "User deleted",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	h.updateUser(w, r, func(id int, vars map[string]string) (entity.User, error) {
+		return h.UserUseCase.Subscribe(id, entity.Category(vars["category"]))
+	})
+}
+
+func (h *UserHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	h.updateUser(w, r, func(id int, vars map[string]string) (entity.User, error) {
+		return h.UserUseCase.Unsubscribe(id, entity.Category(vars["category"]))
+	})
+}
+
+func (h *UserHandler) EnableChannel(w http.ResponseWriter, r *http.Request) {
+	h.updateUser(w, r, func(id int, vars map[string]string) (entity.User, error) {
+		return h.UserUseCase.EnableChannel(id, entity.Channel(vars["channel"]))
+	})
+}
+
+func (h *UserHandler) DisableChannel(w http.ResponseWriter, r *http.Request) {
+	h.updateUser(w, r, func(id int, vars map[string]string) (entity.User, error) {
+		return h.UserUseCase.DisableChannel(id, entity.Channel(vars["channel"]))
+	})
+}
+
+func (h *UserHandler) GetDevices(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	devices, err := h.UserUseCase.GetDevices(id)
+	if err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	response := make([]deviceResponse, 0, len(devices))
+	for _, device := range devices {
+		response = append(response, newDeviceResponse(device))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody deviceRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	device, err := h.UserUseCase.RegisterDevice(id, entity.Device{
+		Token:    requestBody.Token,
+		Platform: requestBody.Platform,
+	})
+	if err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newDeviceResponse(device))
+}
+
+func (h *UserHandler) UnregisterDevice(w http.ResponseWriter, r *http.Request) {
+	id, err := userID(r)
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.UserUseCase.UnregisterDevice(id, mux.Vars(r)["token"]); err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	response := struct {
+		Message string `json:"message"`
+	}{
+		Message: "Device unregistered",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/users", h.GetUsers).Methods(http.MethodGet)
+	router.HandleFunc("/users", h.CreateUser).Methods(http.MethodPost)
+	router.HandleFunc("/users/{id}", h.GetUser).Methods(http.MethodGet)
+	router.HandleFunc("/users/{id}", h.UpdateUser).Methods(http.MethodPut)
+	router.HandleFunc("/users/{id}", h.DeleteUser).Methods(http.MethodDelete)
+	router.HandleFunc("/users/{id}/subscriptions/{category}", h.Subscribe).Methods(http.MethodPut)
+	router.HandleFunc("/users/{id}/subscriptions/{category}", h.Unsubscribe).Methods(http.MethodDelete)
+	router.HandleFunc("/users/{id}/channels/{channel}", h.EnableChannel).Methods(http.MethodPut)
+	router.HandleFunc("/users/{id}/channels/{channel}", h.DisableChannel).Methods(http.MethodDelete)
+	router.HandleFunc("/users/{id}/devices", h.GetDevices).Methods(http.MethodGet)
+	router.HandleFunc("/users/{id}/devices", h.RegisterDevice).Methods(http.MethodPost)
+	router.HandleFunc("/users/{id}/devices/{token}", h.UnregisterDevice).Methods(http.MethodDelete)
+}
+
+func (h *UserHandler) updateUser(w http.ResponseWriter, r *http.Request, update func(int, map[string]string) (entity.User, error)) {
+	id, err := userID(r)
+	if err != nil {
+		http.Error(w, "Invalid user id", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := update(id, mux.Vars(r))
+	if err != nil {
+		writeUserError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newUserResponse(updated))
+}
+
+func (b userRequest) toUser(id int) entity.User {
+	return entity.User{
+		ID:          id,
+		Name:        b.Name,
+		Email:       b.Email,
+		PhoneNumber: b.PhoneNumber,
+		Locale:      b.Locale,
+		Timezone:    b.Timezone,
+		QuietHours:  b.quietHours(),
+		Subscribed:  b.Subscribed,
+		Channels:    b.Channels,
+		Fallback:    b.Fallback,
+	}
+}
+
+func (b userRequest) quietHours() *entity.QuietHours {
+	if b.QuietHours == nil {
+		return nil
+	}
+
+	return &entity.QuietHours{Start: b.QuietHours.Start, End: b.QuietHours.End}
+}
+
+func newUserResponse(user entity.User) userResponse {
+	response := userResponse{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		PhoneNumber: user.PhoneNumber,
+		Locale:      user.Locale,
+		Timezone:    user.Timezone,
+		Subscribed:  user.Subscribed,
+		Channels:    user.Channels,
+		Fallback:    user.Fallback,
+	}
+	if user.QuietHours != nil {
+		response.QuietHours = &quietHours{Start: user.QuietHours.Start, End: user.QuietHours.End}
+	}
+
+	return response
+}
+
+func newDeviceResponse(device entity.Device) deviceResponse {
+	return deviceResponse{
+		Token:    device.Token,
+		UserID:   device.UserID,
+		Platform: device.Platform,
+		LastSeen: device.LastSeen,
+	}
+}
+
+func decodeUserRequest(r *http.Request) (userRequest, error) {
+	var requestBody userRequest
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&requestBody)
+	return requestBody, err
+}
+
+func userID(r *http.Request) (int, error) {
+	return strconv.Atoi(mux.Vars(r)["id"])
+}
+
+func writeUserError(w http.ResponseWriter, err error) {
+	var validationError user.ValidationError
+
+	switch {
+	case errors.As(err, &validationError):
+		http.Error(w, validationError.Message, http.StatusBadRequest)
+	case errors.Is(err, log.ErrUserNotFound):
+		http.Error(w, "User not found", http.StatusNotFound)
+	case errors.Is(err, log.ErrDeviceNotFound):
+		http.Error(w, "Device not found", http.StatusNotFound)
+	case errors.Is(err, user.ErrNotSubscribed):
+		http.Error(w, "User not subscribed to category", http.StatusNotFound)
+	case errors.Is(err, user.ErrChannelDisabled):
+		http.Error(w, "Channel not enabled", http.StatusNotFound)
+	case errors.Is(err, log.ErrUserAlreadyExists):
+		http.Error(w, "User already exists", http.StatusConflict)
+	case errors.Is(err, user.ErrAlreadySubscribed):
+		http.Error(w, "User already subscribed to category", http.StatusConflict)
+	case errors.Is(err, user.ErrChannelEnabled):
+		http.Error(w, "Channel already enabled", http.StatusConflict)
+	default:
+		http.Error(w, "Failed to update users", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,204 @@
+package notification_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/user"
+	log "notification/test/platform"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	userRouter *mux.Router
+	deviceMock *log.MockDevice
+)
+
+func TestCreateUser_Success(t *testing.T) {
+	bodyReader := strings.NewReader(`{"name": "Mary Alexander", "email": "mary.alexander@outlook.com", "phone_number": "78958745", "subscribed": ["Sports"], "channels": ["SMS"]}`)
+	r := httptest.NewRequest(http.MethodPost, "/users", bodyReader)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().CreateUser(getUser(0)).Return(getUser(1), nil)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusCreated, got.StatusCode)
+
+	var created userResponse
+	err := json.NewDecoder(got.Body).Decode(&created)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+	assert.Equal(t, "78958745", created.PhoneNumber)
+	controller.Finish()
+}
+
+func TestCreateUser_Body_Error(t *testing.T) {
+	bodyReader := strings.NewReader(`{"name": "Mary Alexander", "unknown": true}`)
+	r := httptest.NewRequest(http.MethodPost, "/users", bodyReader)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusBadRequest, got.StatusCode)
+	controller.Finish()
+}
+
+func TestCreateUser_Conflict_Error(t *testing.T) {
+	bodyReader := strings.NewReader(`{"id": 1, "name": "Mary Alexander", "phone_number": "78958745", "channels": ["SMS"]}`)
+	r := httptest.NewRequest(http.MethodPost, "/users", bodyReader)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().CreateUser(gomock.Any()).Return(entity.User{}, repositories.ErrUserAlreadyExists)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusConflict, got.StatusCode)
+	controller.Finish()
+}
+
+func TestGetUser_NotFound_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().GetUser(7).Return(entity.User{}, repositories.ErrUserNotFound)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	controller.Finish()
+}
+
+func TestUpdateAndDeleteUser_Success(t *testing.T) {
+	bodyReader := strings.NewReader(`{"name": "Mary Alexander", "email": "mary.alexander@outlook.com", "phone_number": "78958745", "subscribed": ["Sports"], "channels": ["SMS"]}`)
+	r := httptest.NewRequest(http.MethodPut, "/users/1", bodyReader)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	// PUT
+	userMock.EXPECT().UpdateUser(getUser(1)).Return(nil)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	// DELETE
+	r = httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	w = httptest.NewRecorder()
+
+	userMock.EXPECT().GetUser(1).Return(getUser(1), nil)
+	deviceMock.EXPECT().DeleteDevicesByUser(1).Return(nil)
+	userMock.EXPECT().DeleteUser(1).Return(nil)
+	userRouter.ServeHTTP(w, r)
+
+	got = w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+	controller.Finish()
+}
+
+func TestSubscribe_Success(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPut, "/users/1/subscriptions/Movies", nil)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().GetUser(1).Return(getUser(1), nil)
+	userMock.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusOK, got.StatusCode)
+
+	var updated userResponse
+	err := json.NewDecoder(got.Body).Decode(&updated)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Category{entity.SportsCategory, entity.MoviesCategory}, updated.Subscribed)
+	controller.Finish()
+}
+
+func TestSubscribe_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPut, "/users/1/subscriptions/Sports", nil)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().GetUser(1).Return(getUser(1), nil)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusConflict, got.StatusCode)
+
+	r = httptest.NewRequest(http.MethodPut, "/users/1/subscriptions/Cooking", nil)
+	w = httptest.NewRecorder()
+	userRouter.ServeHTTP(w, r)
+
+	got = w.Result()
+	assert.Equal(t, http.StatusBadRequest, got.StatusCode)
+	controller.Finish()
+}
+
+func TestDisableChannel_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodDelete, "/users/1/channels/Push", nil)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().GetUser(1).Return(getUser(1), nil)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	controller.Finish()
+}
+
+func TestRegisterDevice_Success(t *testing.T) {
+	bodyReader := strings.NewReader(`{"token": "device-token", "platform": "Android"}`)
+	r := httptest.NewRequest(http.MethodPost, "/users/1/devices", bodyReader)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().GetUser(1).Return(getUser(1), nil)
+	deviceMock.EXPECT().SaveDevice(gomock.Any()).Return(nil)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusCreated, got.StatusCode)
+
+	var device deviceResponse
+	err := json.NewDecoder(got.Body).Decode(&device)
+	assert.NoError(t, err)
+	assert.Equal(t, "device-token", device.Token)
+	assert.Equal(t, 1, device.UserID)
+	controller.Finish()
+}
+
+func TestUnregisterDevice_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodDelete, "/users/1/devices/device-token", nil)
+	w := httptest.NewRecorder()
+	setUserHandlerAndMock(t)
+
+	userMock.EXPECT().GetUser(1).Return(getUser(1), nil)
+	deviceMock.EXPECT().GetDevicesByUser(1).Return(nil, nil)
+	userRouter.ServeHTTP(w, r)
+
+	got := w.Result()
+	assert.Equal(t, http.StatusNotFound, got.StatusCode)
+	controller.Finish()
+}
+
+func setUserHandlerAndMock(t *testing.T) {
+	controller = gomock.NewController(t)
+	userMock = log.NewMockUser(controller)
+	deviceMock = log.NewMockDevice(controller)
+	userRouter = mux.NewRouter()
+	NewUserHandler(user.NewUserUseCase(userMock, deviceMock)).RegisterRoutes(userRouter)
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+)
+
+// APIKey stores only the SHA-256 Hash of the key; Prefix identifies it in listings.
+type APIKey struct {
+	ID        string
+	Name      string
+	Prefix    string
+	Hash      string
+	Scopes    []Scope
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+type Scope string
+
+const (
+	ScopeSendNotifications Scope = "notifications:send"
+	ScopeReadLogs          Scope = "logs:read"
+	ScopeDeleteLogs        Scope = "logs:delete"
+	ScopeWriteReceipts     Scope = "receipts:write"
+	ScopeManageUsers       Scope = "users:manage"
+	ScopeManageTemplates   Scope = "templates:manage"
+	ScopeManageKeys        Scope = "keys:manage"
+)
+
+var Scopes = []Scope{ScopeSendNotifications, ScopeReadLogs, ScopeDeleteLogs, ScopeWriteReceipts, ScopeManageUsers, ScopeManageTemplates, ScopeManageKeys}
+
+func (s Scope) IsValid() bool {
+	for _, scope := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k APIKey) HasScope(scope Scope) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+package entity
+
+// Content is a message rendered for one recipient. Reference is the ID of its log.
+type Content struct {
+	Subject   string
+	Title     string
+	Body      string
+	HTML      string
+	Locale    string
+	Reference string
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+type DeadLetter struct {
+	ID             string
+	Notification   Notification
+	UserID         int
+	Channel        Channel
+	Error          string
+	Attempts       int
+	ReplayingSince time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IsReplaying reports whether a replay claimed it less than lease ago.
+func (d DeadLetter) IsReplaying(now time.Time, lease time.Duration) bool {
+	return !d.ReplayingSince.IsZero() && now.Sub(d.ReplayingSince) < lease
+}
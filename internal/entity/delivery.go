@@ -0,0 +1,21 @@
+package entity
+
+type Delivery struct {
+	UserID           int
+	Channel          Channel
+	NotificationType string
+	Attempts         int
+	Error            string
+	DeadLetterID     string
+}
+
+type Result struct {
+	Logs   []Log
+	Failed []Delivery
+}
+
+type NotificationDeliveries struct {
+	ID          string
+	Logs        []Log
+	DeadLetters []DeadLetter
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+)
+
+type Device struct {
+	Token    string
+	UserID   int
+	Platform Platform
+	LastSeen time.Time
+}
+
+type Platform string
+
+const (
+	IOSPlatform     Platform = "iOS"
+	AndroidPlatform Platform = "Android"
+	WebPlatform     Platform = "Web"
+)
+
+var Platforms = []Platform{IOSPlatform, AndroidPlatform, WebPlatform}
+
+func (p Platform) IsValid() bool {
+	for _, platform := range Platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
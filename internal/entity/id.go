@@ -0,0 +1,10 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+// NewID returns a UUIDv7, which sorts by creation time.
+func NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
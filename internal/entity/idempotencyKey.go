@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+)
+
+// IdempotencyKey keeps the Response of a request; it is nil while the request runs.
+type IdempotencyKey struct {
+	Caller      string
+	Key         string
+	Fingerprint string
+	Response    *StoredResponse
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+type StoredResponse struct {
+	StatusCode int
+	Header     map[string]string
+	Body       string
+}
+
+func (k IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+)
+
+type Job struct {
+	ID           string
+	Notification Notification
+	DeadLetterID string
+	Status       JobStatus
+	Total        int
+	Processed    int
+	Logs         []Log
+	Failed       []Delivery
+	Error        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+func (j Job) IsFinished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed
+}
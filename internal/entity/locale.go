@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+)
+
+const DefaultLocale = "en"
+
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+func IsValidLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// LocaleChain returns the locales to try: "pt-BR" gives pt-BR, pt and en.
+func LocaleChain(locale string) []string {
+	var chain []string
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	for locale != "" {
+		chain = append(chain, locale)
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+
+	if findLocale(DefaultLocale, chain) == "" {
+		chain = append(chain, DefaultLocale)
+	}
+
+	return chain
+}
+
+// MatchLocale returns the first locale of the chain found in available, or "".
+func MatchLocale(locale string, available []string) string {
+	for _, candidate := range LocaleChain(locale) {
+		if match := findLocale(candidate, available); match != "" {
+			return match
+		}
+	}
+
+	return ""
+}
+
+func findLocale(locale string, available []string) string {
+	for _, option := range available {
+		if strings.EqualFold(locale, option) {
+			return option
+		}
+	}
+
+	return ""
+}
@@ -6,9 +6,83 @@ import (
 
 type Log struct {
 	ID               string
+	NotificationID   string
 	UserID           int
 	Message          string
 	Category         Category
 	NotificationType string
+	Locale           string
+	Status           LogStatus
+	Error            string
 	Timestamp        time.Time
+	History          []StatusChange
+	RequestedBy      string
+}
+
+type StatusChange struct {
+	Status    LogStatus
+	Timestamp time.Time
+}
+
+type LogStatus string
+
+const (
+	LogQueued     LogStatus = "queued"
+	LogSent       LogStatus = "sent"
+	LogDelivered  LogStatus = "delivered"
+	LogOpened     LogStatus = "opened"
+	LogBounced    LogStatus = "bounced"
+	LogDeferred   LogStatus = "deferred"
+	LogFailed     LogStatus = "failed"
+	LogDropped    LogStatus = "dropped"
+	LogDowngraded LogStatus = "downgraded"
+)
+
+var LogStatuses = []LogStatus{LogQueued, LogSent, LogDelivered, LogOpened, LogBounced, LogDeferred, LogFailed, LogDropped, LogDowngraded}
+
+// logTransitions lists the statuses a delivery can move to from each status.
+var logTransitions = map[LogStatus][]LogStatus{
+	"":           {LogDelivered, LogOpened, LogBounced, LogFailed},
+	LogQueued:    {LogSent, LogDelivered, LogOpened, LogBounced, LogFailed},
+	LogSent:      {LogDelivered, LogOpened, LogBounced, LogFailed},
+	LogDelivered: {LogOpened, LogBounced},
+}
+
+func (s LogStatus) IsValid() bool {
+	for _, status := range LogStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Queued returns the log with its History starting at the time it was queued.
+func (l Log) Queued(at time.Time) Log {
+	l.History = []StatusChange{
+		{Status: LogQueued, Timestamp: at},
+		{Status: l.Status, Timestamp: l.Timestamp},
+	}
+	return l
+}
+
+// Transition reports false when the log can't move to status from its current one.
+func (l Log) Transition(status LogStatus, at time.Time) (Log, bool) {
+	allowed := false
+	for _, next := range logTransitions[l.Status] {
+		if next == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return l, false
+	}
+
+	history := make([]StatusChange, 0, len(l.History)+2)
+	if len(l.History) == 0 {
+		history = append(history, StatusChange{Status: l.Status, Timestamp: l.Timestamp})
+	}
+	l.History = append(append(history, l.History...), StatusChange{Status: status, Timestamp: at})
+	l.Status = status
+	return l, true
 }
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+type SortOrder string
+
+const (
+	SortDescending SortOrder = "desc"
+	SortAscending  SortOrder = "asc"
+)
+
+// LogFilter fields with zero values don't filter. From is inclusive and To exclusive.
+type LogFilter struct {
+	ID               string
+	NotificationID   string
+	UserID           int
+	Category         Category
+	NotificationType string
+	Status           LogStatus
+	From             time.Time
+	To               time.Time
+	Search           string
+	Sort             SortOrder
+	Offset           int
+	Limit            int
+}
+
+func (s SortOrder) IsValid() bool {
+	return s == "" || s == SortDescending || s == SortAscending
+}
+
+func (f LogFilter) Matches(log Log) bool {
+	if f.ID != "" && log.ID != f.ID {
+		return false
+	}
+
+	if f.NotificationID != "" && log.NotificationID != f.NotificationID {
+		return false
+	}
+
+	if f.UserID != 0 && log.UserID != f.UserID {
+		return false
+	}
+
+	if f.Category != "" && log.Category != f.Category {
+		return false
+	}
+
+	if f.NotificationType != "" && log.NotificationType != f.NotificationType {
+		return false
+	}
+
+	if f.Status != "" && log.Status != f.Status {
+		return false
+	}
+
+	if !f.From.IsZero() && log.Timestamp.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && !log.Timestamp.Before(f.To) {
+		return false
+	}
+
+	if f.Search != "" && !strings.Contains(strings.ToLower(log.Message), strings.ToLower(f.Search)) {
+		return false
+	}
+
+	return true
+}
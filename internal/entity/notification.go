@@ -1,6 +1,60 @@
 package entity
 
+import (
+	"sort"
+	"time"
+)
+
+// Notification is sent as Message or rendered from the template TemplateID with Data.
 type Notification struct {
-	Message  string
-	Category Category
+	ID          string
+	Message     string
+	Messages    map[string]string
+	Category    Category
+	TemplateID  string
+	Data        map[string]interface{}
+	Priority    Priority
+	Urgent      bool
+	RequestedBy string
+	QueuedAt    time.Time
+}
+
+// EffectivePriority reads the deprecated Urgent as critical.
+func (n Notification) EffectivePriority() Priority {
+	if n.Urgent {
+		return PriorityCritical
+	}
+
+	return n.Priority
+}
+
+func (n Notification) IsCritical() bool {
+	return n.EffectivePriority() == PriorityCritical
+}
+
+// Localize returns the message for a recipient's locale and the locale it is written in.
+func (n Notification) Localize(locale string) (string, string) {
+	available := make([]string, 0, len(n.Messages))
+	for messageLocale := range n.Messages {
+		available = append(available, messageLocale)
+	}
+	sort.Strings(available)
+
+	if match := MatchLocale(locale, available); match != "" {
+		return n.Messages[match], match
+	}
+
+	if n.Message != "" || len(available) == 0 {
+		return n.Message, DefaultLocale
+	}
+
+	if match := MatchLocale(DefaultLocale, available); match != "" {
+		return n.Messages[match], match
+	}
+
+	return n.Messages[available[0]], available[0]
+}
+
+func (n Notification) HasContent() bool {
+	return n.Message != "" || len(n.Messages) > 0 || n.TemplateID != ""
 }
@@ -0,0 +1,40 @@
+package entity
+
+type Principal struct {
+	Subject string
+	Tenant  string
+	Scopes  []Scope
+}
+
+type Role string
+
+const (
+	RoleSender        Role = "sender"
+	RoleLogReader     Role = "log_reader"
+	RoleLogAdmin      Role = "log_admin"
+	RoleUserAdmin     Role = "user_admin"
+	RoleTemplateAdmin Role = "template_admin"
+	RoleReceiptWriter Role = "receipt_writer"
+)
+
+var roleScopes = map[Role][]Scope{
+	RoleSender:        {ScopeSendNotifications},
+	RoleLogReader:     {ScopeReadLogs},
+	RoleLogAdmin:      {ScopeReadLogs, ScopeDeleteLogs},
+	RoleUserAdmin:     {ScopeManageUsers},
+	RoleTemplateAdmin: {ScopeManageTemplates},
+	RoleReceiptWriter: {ScopeWriteReceipts},
+}
+
+func (r Role) Scopes() []Scope {
+	return roleScopes[r]
+}
+
+func (p Principal) HasScope(scope Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
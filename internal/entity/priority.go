@@ -0,0 +1,45 @@
+package entity
+
+type Priority string
+
+const (
+	PriorityLow      Priority = "low"
+	PriorityNormal   Priority = "normal"
+	PriorityHigh     Priority = "high"
+	PriorityCritical Priority = "critical"
+)
+
+var Priorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityCritical}
+
+func (p Priority) IsValid() bool {
+	return p == "" || p.Rank() > 0
+}
+
+func (p Priority) Rank() int {
+	if p == "" {
+		p = PriorityNormal
+	}
+
+	for i, priority := range Priorities {
+		if p == priority {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ReachableChannels returns every channel the user can be reached on, enabled ones first.
+func (u User) ReachableChannels(hasDevices bool) []Channel {
+	channels := append([]Channel{}, u.Channels...)
+	if !u.HasChannel(EmailChannel) && u.Email != "" {
+		channels = append(channels, EmailChannel)
+	}
+	if !u.HasChannel(SMSChannel) && u.PhoneNumber != "" {
+		channels = append(channels, SMSChannel)
+	}
+	if !u.HasChannel(PushChannel) && hasDevices {
+		channels = append(channels, PushChannel)
+	}
+
+	return channels
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"time"
+)
+
+const clockLayout = "15:04"
+
+// QuietHours is a daily "15:04" window in the user's Timezone; End is exclusive.
+type QuietHours struct {
+	Start string
+	End   string
+}
+
+func (q QuietHours) IsValid() bool {
+	start, startErr := time.Parse(clockLayout, q.Start)
+	end, endErr := time.Parse(clockLayout, q.End)
+	return startErr == nil && endErr == nil && !start.Equal(end)
+}
+
+func (u User) Location() (*time.Location, error) {
+	return time.LoadLocation(u.Timezone)
+}
+
+// QuietUntil reports whether now falls within quiet hours and when they end.
+func (u User) QuietUntil(now time.Time) (time.Time, bool) {
+	if u.QuietHours == nil || !u.QuietHours.IsValid() {
+		return time.Time{}, false
+	}
+
+	location, err := u.Location()
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := now.In(location)
+	start, _ := time.Parse(clockLayout, u.QuietHours.Start)
+	end, _ := time.Parse(clockLayout, u.QuietHours.End)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	quiet := minute >= startMinute && minute < endMinute
+	if startMinute > endMinute {
+		quiet = minute >= startMinute || minute < endMinute
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, location)
+	if !until.After(local) {
+		until = time.Date(local.Year(), local.Month(), local.Day()+1, end.Hour(), end.Minute(), 0, 0, location)
+	}
+
+	return until, true
+}
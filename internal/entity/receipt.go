@@ -0,0 +1,12 @@
+package entity
+
+import (
+	"time"
+)
+
+type Receipt struct {
+	LogID     string
+	Status    LogStatus
+	Error     string
+	Timestamp time.Time
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+)
+
+// Schedule with a UserID is a delivery deferred to Channel, not part of the schedule API.
+type Schedule struct {
+	ID           string
+	Notification Notification
+	UserID       int
+	Channel      Channel
+	SendAt       time.Time
+	Cron         string
+	Status       ScheduleStatus
+	NextRun      time.Time
+	LastRun      time.Time
+	Runs         int
+	Error        string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type ScheduleStatus string
+
+const (
+	ScheduleActive    ScheduleStatus = "scheduled"
+	ScheduleCompleted ScheduleStatus = "completed"
+	ScheduleCancelled ScheduleStatus = "cancelled"
+)
+
+func (s Schedule) IsRecurring() bool {
+	return s.Cron != ""
+}
+
+func (s Schedule) IsDeferredDelivery() bool {
+	return s.UserID != 0
+}
+
+func (s Schedule) IsFinished() bool {
+	return s.Status == ScheduleCompleted || s.Status == ScheduleCancelled
+}
+
+func (s Schedule) IsDue(now time.Time) bool {
+	return s.Status == ScheduleActive && !s.NextRun.After(now)
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+type Template struct {
+	ID           string
+	Locale       string
+	Body         string
+	SMS          SMSTemplate
+	Email        EmailTemplate
+	Push         PushTemplate
+	Translations map[string]TemplateTranslation
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type TemplateTranslation struct {
+	Body  string
+	SMS   SMSTemplate
+	Email EmailTemplate
+	Push  PushTemplate
+}
+
+type SMSTemplate struct {
+	Body string
+}
+
+type EmailTemplate struct {
+	Subject string
+	Body    string
+	HTML    string
+}
+
+type PushTemplate struct {
+	Title string
+	Body  string
+}
+
+// Localize returns the bodies for a recipient's locale, completed with the template's own.
+func (t Template) Localize(locale string) (TemplateTranslation, string) {
+	base := t.Locale
+	if base == "" {
+		base = DefaultLocale
+	}
+
+	available := []string{base}
+	for translation := range t.Translations {
+		available = append(available, translation)
+	}
+	sort.Strings(available[1:])
+
+	own := TemplateTranslation{
+		Body:  t.Body,
+		SMS:   t.SMS,
+		Email: t.Email,
+		Push:  t.Push,
+	}
+
+	match := MatchLocale(locale, available)
+	if translation, ok := t.Translations[match]; ok && match != base {
+		return translation.complete(own), match
+	}
+
+	return own, base
+}
+
+func (t TemplateTranslation) complete(base TemplateTranslation) TemplateTranslation {
+	if t.Body == "" {
+		t.Body = base.Body
+		t.SMS.Body = firstNonEmpty(t.SMS.Body, base.SMS.Body)
+		t.Email.Body = firstNonEmpty(t.Email.Body, base.Email.Body)
+		t.Push.Body = firstNonEmpty(t.Push.Body, base.Push.Body)
+	}
+
+	t.Email.Subject = firstNonEmpty(t.Email.Subject, base.Email.Subject)
+	t.Email.HTML = firstNonEmpty(t.Email.HTML, base.Email.HTML)
+	t.Push.Title = firstNonEmpty(t.Push.Title, base.Push.Title)
+
+	return t
+}
+
+func firstNonEmpty(value string, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
@@ -5,8 +5,12 @@ type User struct {
 	Name        string
 	Email       string
 	PhoneNumber string
+	Locale      string
+	Timezone    string
+	QuietHours  *QuietHours
 	Subscribed  []Category
 	Channels    []Channel
+	Fallback    []Channel
 }
 
 type Category string
@@ -17,6 +21,8 @@ const (
 	MoviesCategory  Category = "Movies"
 )
 
+var Categories = []Category{SportsCategory, FinanceCategory, MoviesCategory}
+
 type Channel string
 
 const (
@@ -24,3 +30,46 @@ const (
 	PushChannel  Channel = "Push"
 	SMSChannel   Channel = "SMS"
 )
+
+var Channels = []Channel{EmailChannel, PushChannel, SMSChannel}
+
+func (c Category) IsValid() bool {
+	for _, category := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIntrusive reports whether the channel is held back during quiet hours.
+func (c Channel) IsIntrusive() bool {
+	return c == SMSChannel || c == PushChannel
+}
+
+func (c Channel) IsValid() bool {
+	for _, channel := range Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+func (u User) IsSubscribed(category Category) bool {
+	for _, subscribed := range u.Subscribed {
+		if subscribed == category {
+			return true
+		}
+	}
+	return false
+}
+
+func (u User) HasChannel(channel Channel) bool {
+	for _, enabled := range u.Channels {
+		if enabled == channel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"notification/internal/entity"
+	"path/filepath"
+	"time"
+)
+
+// Config holds every setting the server starts with. It is read from a YAML
+// or JSON file, then from environment variables, and validated before use;
+// see Load.
+type Config struct {
+	Server          ServerConfig    `yaml:"server" json:"server"`
+	Auth            AuthConfig      `yaml:"auth" json:"auth"`
+	Storage         StorageConfig   `yaml:"storage" json:"storage"`
+	Providers       ProvidersConfig `yaml:"providers" json:"providers"`
+	Workers         WorkersConfig   `yaml:"workers" json:"workers"`
+	Retention       RetentionConfig `yaml:"retention" json:"retention"`
+	IdempotencyTTL  Duration        `yaml:"idempotency_ttl" json:"idempotency_ttl"`
+	FallbackTimeout Duration        `yaml:"fallback_timeout" json:"fallback_timeout"`
+	// FallbackChains lists, for a category, the channels tried in turn
+	// until one delivers.
+	FallbackChains map[entity.Category][]entity.Channel `yaml:"fallback_chains" json:"fallback_chains"`
+	RateLimits     map[entity.Channel]RateLimitConfig   `yaml:"rate_limits" json:"rate_limits"`
+}
+
+type ServerConfig struct {
+	Address            string    `yaml:"address" json:"address"`
+	TLS                TLSConfig `yaml:"tls" json:"tls"`
+	CORSAllowedOrigins []string  `yaml:"cors_allowed_origins" json:"cors_allowed_origins"`
+}
+
+// TLSConfig serves HTTPS when both files are set.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" json:"cert_file"`
+	KeyFile  string `yaml:"key_file" json:"key_file"`
+}
+
+type AuthConfig struct {
+	AdminAPIKey string    `yaml:"admin_api_key" json:"admin_api_key"`
+	JWT         JWTConfig `yaml:"jwt" json:"jwt"`
+}
+
+// JWTConfig accepts JWTs when Secrets or JWKSPath is set.
+type JWTConfig struct {
+	Secrets     []string `yaml:"secrets" json:"secrets"`
+	JWKSPath    string   `yaml:"jwks_path" json:"jwks_path"`
+	Audience    string   `yaml:"audience" json:"audience"`
+	Tenant      string   `yaml:"tenant" json:"tenant"`
+	RolesClaim  string   `yaml:"roles_claim" json:"roles_claim"`
+	TenantClaim string   `yaml:"tenant_claim" json:"tenant_claim"`
+	Leeway      Duration `yaml:"leeway" json:"leeway"`
+}
+
+// StorageConfig keeps every file in DataDir unless a path of its own is
+// given. Jobs are kept in memory when JobQueuePath is empty.
+type StorageConfig struct {
+	DataDir      string     `yaml:"data_dir" json:"data_dir"`
+	Logs         LogsConfig `yaml:"logs" json:"logs"`
+	JobQueuePath string     `yaml:"job_queue_path" json:"job_queue_path"`
+}
+
+type LogsConfig struct {
+	Backend LogBackend `yaml:"backend" json:"backend"`
+	Path    string     `yaml:"path" json:"path"`
+}
+
+type LogBackend string
+
+const (
+	FileBackend   LogBackend = "file"
+	JSONLBackend  LogBackend = "jsonl"
+	SQLiteBackend LogBackend = "sqlite"
+)
+
+// ProvidersConfig holds the provider credentials. A provider that isn't
+// configured prints its messages to stdout.
+type ProvidersConfig struct {
+	SMTP SMTPConfig `yaml:"smtp" json:"smtp"`
+	SMS  SMSConfig  `yaml:"sms" json:"sms"`
+	Push PushConfig `yaml:"push" json:"push"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" json:"host"`
+	Port     int    `yaml:"port" json:"port"`
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+	From     string `yaml:"from" json:"from"`
+	Subject  string `yaml:"subject" json:"subject"`
+	TLS      string `yaml:"tls" json:"tls"`
+}
+
+// SMSConfig configures the SMS gateway. StatusCallback is the public URL of
+// the SMS receipt webhook, passed to the gateway with every message.
+type SMSConfig struct {
+	GatewayURL     string `yaml:"gateway_url" json:"gateway_url"`
+	AccountID      string `yaml:"account_id" json:"account_id"`
+	AuthToken      string `yaml:"auth_token" json:"auth_token"`
+	From           string `yaml:"from" json:"from"`
+	CountryCode    string `yaml:"country_code" json:"country_code"`
+	StatusCallback string `yaml:"status_callback" json:"status_callback"`
+}
+
+type PushConfig struct {
+	Endpoint    string `yaml:"endpoint" json:"endpoint"`
+	AccessToken string `yaml:"access_token" json:"access_token"`
+	Title       string `yaml:"title" json:"title"`
+}
+
+// WorkersConfig sizes the dispatch pool. Low priority jobs are held back for
+// LowPriorityDelay; BatchWindow is its deprecated name. Finished jobs are kept
+// for JobRetention and finished schedules for ScheduleRetention, or forever
+// when it is 0.
+type WorkersConfig struct {
+	Dispatch          int      `yaml:"dispatch" json:"dispatch"`
+	LowPriorityDelay  Duration `yaml:"low_priority_delay" json:"low_priority_delay"`
+	BatchWindow       Duration `yaml:"batch_window" json:"batch_window"`
+	JobRetention      Duration `yaml:"job_retention" json:"job_retention"`
+	ScheduleInterval  Duration `yaml:"schedule_interval" json:"schedule_interval"`
+	ScheduleRetention Duration `yaml:"schedule_retention" json:"schedule_retention"`
+}
+
+// RateLimitConfig limits a channel for each user and for its provider as a
+// whole. Messages over a limit are handled by Policy: drop, the default,
+// defer or downgrade, which sends them on the Downgrade channel instead.
+type RateLimitConfig struct {
+	User      Limit          `yaml:"user" json:"user"`
+	Provider  Limit          `yaml:"provider" json:"provider"`
+	Policy    string         `yaml:"policy" json:"policy"`
+	Downgrade entity.Channel `yaml:"downgrade" json:"downgrade"`
+}
+
+// RetentionConfig prunes logs older than Days every Interval; logs are kept
+// forever when Days is 0.
+type RetentionConfig struct {
+	Days     int      `yaml:"days" json:"days"`
+	Interval Duration `yaml:"interval" json:"interval"`
+}
+
+// Default returns the settings used for anything the file and the
+// environment leave out.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address: ":8080",
+		},
+		Storage: StorageConfig{
+			Logs: LogsConfig{Backend: FileBackend},
+		},
+		Workers: WorkersConfig{
+			Dispatch:          4,
+			JobRetention:      Duration(24 * time.Hour),
+			ScheduleInterval:  Duration(time.Second),
+			ScheduleRetention: Duration(7 * 24 * time.Hour),
+		},
+		Retention: RetentionConfig{
+			Interval: Duration(time.Hour),
+		},
+		IdempotencyTTL: Duration(24 * time.Hour),
+	}
+}
+
+// File returns the path of a storage file kept in DataDir.
+func (s StorageConfig) File(name string) string {
+	return filepath.Join(s.DataDir, name)
+}
+
+// LogPath returns the configured log path or the backend's default file.
+func (s StorageConfig) LogPath() string {
+	if s.Logs.Path != "" {
+		return s.Logs.Path
+	}
+
+	switch s.Logs.Backend {
+	case JSONLBackend:
+		return s.File("logs.jsonl")
+	case SQLiteBackend:
+		return s.File("logs.db")
+	default:
+		return s.File("logs.txt")
+	}
+}
+
+func (t TLSConfig) IsEnabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+func (j JWTConfig) IsEnabled() bool {
+	return len(j.Secrets) > 0 || j.JWKSPath != ""
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"notification/internal/entity"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_YAML(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "config.yaml", `
+server:
+  address: ":9090"
+  cors_allowed_origins: [https://app.example.com]
+storage:
+  data_dir: .
+  logs:
+    backend: sqlite
+providers:
+  smtp:
+    host: smtp.example.com
+    port: 587
+    from: alerts@example.com
+    tls: starttls
+workers:
+  dispatch: 8
+  low_priority_delay: 50ms
+retention:
+  days: 30
+`)
+
+	config, err := Load(path, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, ":9090", config.Server.Address)
+	assert.Equal(t, []string{"https://app.example.com"}, config.Server.CORSAllowedOrigins)
+	assert.Equal(t, dir, config.Storage.DataDir)
+	assert.Equal(t, filepath.Join(dir, "logs.db"), config.Storage.LogPath())
+	assert.Equal(t, 587, config.Providers.SMTP.Port)
+	assert.Equal(t, 8, config.Workers.Dispatch)
+	assert.Equal(t, 50*time.Millisecond, config.Workers.LowPriorityDelay.Duration())
+	assert.Equal(t, time.Second, config.Workers.ScheduleInterval.Duration())
+	assert.Equal(t, 30, config.Retention.Days)
+}
+
+func TestLoad_JSON(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "config.json", `{
+		"storage": {"data_dir": ".", "logs": {"backend": "jsonl", "path": "logs/all.jsonl"}},
+		"idempotency_ttl": "1h"
+	}`)
+
+	config, err := Load(path, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, JSONLBackend, config.Storage.Logs.Backend)
+	assert.Equal(t, filepath.Join(dir, "logs/all.jsonl"), config.Storage.LogPath())
+	assert.Equal(t, time.Hour, config.IdempotencyTTL.Duration())
+	assert.Equal(t, 4, config.Workers.Dispatch)
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "config.yaml", `
+server:
+  address: ":9090"
+storage:
+  data_dir: .
+workers:
+  dispatch: 8
+`)
+
+	config, err := Load(path, env(map[string]string{
+		"LISTEN_ADDRESS":        "127.0.0.1:7070",
+		"DISPATCH_WORKERS":      "2",
+		"CORS_ALLOWED_ORIGINS":  "https://a.example.com, https://b.example.com",
+		"LOG_STORAGE":           "sqlite",
+		"LOG_SQLITE_PATH":       filepath.Join(dir, "notifications.db"),
+		"DISPATCH_BATCH_WINDOW": "5m",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:7070", config.Server.Address)
+	assert.Equal(t, 2, config.Workers.Dispatch)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, config.Server.CORSAllowedOrigins)
+	assert.Equal(t, filepath.Join(dir, "notifications.db"), config.Storage.LogPath())
+	assert.Equal(t, 5*time.Minute, config.Workers.LowPriorityDelay.Duration())
+}
+
+func TestLoad_WithoutFile(t *testing.T) {
+	config, err := Load("", env(map[string]string{"DATA_DIR": t.TempDir()}))
+	assert.NoError(t, err)
+	assert.Equal(t, ":8080", config.Server.Address)
+	assert.Equal(t, FileBackend, config.Storage.Logs.Backend)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "config.yaml", `
+server:
+  address: "8080"
+  tls:
+    cert_file: cert.pem
+storage:
+  data_dir: .
+  logs:
+    backend: postgres
+providers:
+  sms:
+    gateway_url: api.example.com
+    account_id: AC1
+workers:
+  dispatch: 0
+`)
+
+	_, err := Load(path, env(map[string]string{"SCHEDULE_INTERVAL": "soon"}))
+	assert.Error(t, err)
+
+	configError, ok := err.(Error)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`SCHEDULE_INTERVAL: "soon" is not a duration such as 30s or 1h`,
+		`server.address: "8080" is not a host:port address such as :8080`,
+		`server.tls: cert_file and key_file must be set together`,
+		`server.tls.cert_file: stat ` + filepath.Join(dir, "cert.pem") + `: no such file or directory`,
+		`storage.logs.backend: "postgres" is not one of file, jsonl or sqlite`,
+		`providers.sms.gateway_url: "api.example.com" is not an http or https URL`,
+		`providers.sms.auth_token: is required when gateway_url is set`,
+		`providers.sms.from: is required when gateway_url is set`,
+		`workers.dispatch: must be at least 1`,
+	}, configError.Problems)
+	assert.Contains(t, err.Error(), "invalid configuration:\n  - SCHEDULE_INTERVAL")
+}
+
+func TestLoad_RelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfig(t, dir, "config.yaml", `
+storage:
+  job_queue_path: jobs.json
+`)
+
+	// Only the paths set in the file are relative to it.
+	config, err := Load(path, env(map[string]string{"DATA_DIR": "."}))
+	assert.NoError(t, err)
+	assert.Equal(t, ".", config.Storage.DataDir)
+	assert.Equal(t, filepath.Join(dir, "jobs.json"), config.Storage.JobQueuePath)
+}
+
+func TestLoad_WithoutDataDir(t *testing.T) {
+	_, err := Load("", env(nil))
+	assert.ErrorContains(t, err, "storage.data_dir: is required")
+}
+
+func TestLoad_Limits(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.yaml", `
+storage:
+  data_dir: .
+fallback_chains:
+  Finance: [Push, SMS, Email]
+rate_limits:
+  SMS:
+    user: 5/1h
+    provider: 10/1s
+    policy: downgrade
+    downgrade: Email
+  Push:
+    user: 20/1h
+`)
+
+	config, err := Load(path, env(map[string]string{
+		"RATE_LIMIT_PUSH":        "30/1h",
+		"RATE_LIMIT_POLICY_PUSH": "defer",
+		"PROVIDER_LIMIT_EMAIL":   "100/1m",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[entity.Category][]entity.Channel{
+		entity.FinanceCategory: {entity.PushChannel, entity.SMSChannel, entity.EmailChannel},
+	}, config.FallbackChains)
+	assert.Equal(t, map[entity.Channel]RateLimitConfig{
+		entity.SMSChannel: {
+			User:      Limit{Count: 5, Per: time.Hour},
+			Provider:  Limit{Count: 10, Per: time.Second},
+			Policy:    "downgrade",
+			Downgrade: entity.EmailChannel,
+		},
+		entity.PushChannel:  {User: Limit{Count: 30, Per: time.Hour}, Policy: "defer"},
+		entity.EmailChannel: {Provider: Limit{Count: 100, Per: time.Minute}},
+	}, config.RateLimits)
+
+	config, err = Load(path, env(map[string]string{"FALLBACK_CHAINS": "Sports=Push, Email"}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[entity.Category][]entity.Channel{
+		entity.SportsCategory: {entity.PushChannel, entity.EmailChannel},
+	}, config.FallbackChains)
+}
+
+func TestLoad_InvalidLimits(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.yaml", `
+storage:
+  data_dir: .
+fallback_chains:
+  Weather: [Push]
+  Finance: [Push, Fax]
+rate_limits:
+  SMS:
+    user: 5/1h
+    policy: downgrade
+    downgrade: SMS
+  Push:
+    policy: defer
+`)
+
+	_, err := Load(path, env(map[string]string{
+		"RATE_LIMIT_EMAIL":        "five",
+		"RATE_LIMIT_POLICY_EMAIL": "queue",
+	}))
+	configError, ok := err.(Error)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		`RATE_LIMIT_EMAIL: invalid rate limit "five", use count/duration such as 5/1h`,
+		`fallback_chains.Finance: "Fax" is not a channel`,
+		`fallback_chains.Weather: "Weather" is not a category`,
+		`rate_limits.Email: user or provider is required`,
+		`rate_limits.Email.policy: "queue" is not one of drop, defer or downgrade`,
+		`rate_limits.Push: user or provider is required`,
+		`rate_limits.SMS.downgrade: "SMS" is not another channel`,
+	}, configError.Problems)
+
+	path = writeConfig(t, t.TempDir(), "config.json", `{"rate_limits": {"SMS": {"user": "5 per hour"}}}`)
+	_, err = Load(path, env(nil))
+	assert.ErrorContains(t, err, "Failed to parse config file")
+}
+
+func TestLoad_UnknownKey(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.yaml", `
+server:
+  adress: ":9090"
+`)
+
+	_, err := Load(path, env(nil))
+	assert.ErrorContains(t, err, "field adress not found")
+}
+
+func TestLoad_InvalidDuration(t *testing.T) {
+	path := writeConfig(t, t.TempDir(), "config.json", `{"idempotency_ttl": 3600}`)
+
+	_, err := Load(path, env(nil))
+	assert.ErrorContains(t, err, "Failed to parse config file")
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "config.yaml"), env(nil))
+	assert.ErrorContains(t, err, "Failed to open config file")
+}
+
+func writeConfig(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func env(values map[string]string) func(string) string {
+	return func(name string) string {
+		return values[name]
+	}
+}
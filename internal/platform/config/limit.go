@@ -0,0 +1,40 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"notification/internal/usecase/ratelimit"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Limit is written as a count per duration, such as "5/1h", in both YAML
+// and JSON files.
+type Limit ratelimit.Limit
+
+func (l Limit) Limit() ratelimit.Limit {
+	return ratelimit.Limit(l)
+}
+
+func (l *Limit) UnmarshalYAML(value *yaml.Node) error {
+	return l.parse(value.Value)
+}
+
+func (l *Limit) UnmarshalJSON(content []byte) error {
+	var value string
+	if err := json.Unmarshal(content, &value); err != nil {
+		return fmt.Errorf("rate limits are strings such as \"5/1h\"")
+	}
+
+	return l.parse(value)
+}
+
+func (l *Limit) parse(value string) error {
+	parsed, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		return err
+	}
+
+	*l = Limit(parsed)
+	return nil
+}
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"notification/internal/entity"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error lists every invalid setting, so that they can be fixed at once.
+type Error struct {
+	Problems []string
+}
+
+func (e Error) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load starts from Default, applies the YAML or JSON file at path, when
+// given, and then the environment variables read through getenv, and
+// validates the result. Relative paths set in the file are relative to the
+// file; those from the environment are relative to the working directory.
+// Unknown keys in the file are rejected.
+func Load(path string, getenv func(string) string) (Config, error) {
+	config := Default()
+	if path != "" {
+		// The file is also read on its own to tell which paths it sets.
+		var file Config
+		if err := readFile(path, &config, &file); err != nil {
+			return config, err
+		}
+
+		settings := config.paths()
+		for i, setting := range file.paths() {
+			if *setting != "" && !filepath.IsAbs(*setting) {
+				*settings[i] = filepath.Join(filepath.Dir(path), *setting)
+			}
+		}
+	}
+
+	env := envReader{getenv: getenv}
+	env.apply(&config)
+	if config.Workers.LowPriorityDelay == 0 {
+		config.Workers.LowPriorityDelay = config.Workers.BatchWindow
+	}
+
+	problems := append(env.problems, config.Validate()...)
+	if len(problems) > 0 {
+		return config, Error{Problems: problems}
+	}
+
+	return config, nil
+}
+
+// readFile decodes the file at path into every config given.
+func readFile(path string, configs ...*Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to open config file %s: %v", path, err)
+	}
+
+	for _, config := range configs {
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			decoder := json.NewDecoder(bytes.NewReader(content))
+			decoder.DisallowUnknownFields()
+			err = decoder.Decode(config)
+		} else {
+			decoder := yaml.NewDecoder(bytes.NewReader(content))
+			decoder.KnownFields(true)
+			err = decoder.Decode(config)
+		}
+
+		// An empty YAML file has no document; the defaults apply.
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("Failed to parse config file %s: %v", path, err)
+		}
+	}
+
+	return nil
+}
+
+// paths returns the settings that hold a file or directory path.
+func (c *Config) paths() []*string {
+	return []*string{
+		&c.Server.TLS.CertFile,
+		&c.Server.TLS.KeyFile,
+		&c.Auth.JWT.JWKSPath,
+		&c.Storage.DataDir,
+		&c.Storage.Logs.Path,
+		&c.Storage.JobQueuePath,
+	}
+}
+
+// envReader overrides settings with the environment variables that are set
+// and collects the ones that can't be parsed.
+type envReader struct {
+	getenv   func(string) string
+	problems []string
+}
+
+func (e *envReader) apply(c *Config) {
+	e.string("LISTEN_ADDRESS", &c.Server.Address)
+	e.string("TLS_CERT_FILE", &c.Server.TLS.CertFile)
+	e.string("TLS_KEY_FILE", &c.Server.TLS.KeyFile)
+	e.list("CORS_ALLOWED_ORIGINS", &c.Server.CORSAllowedOrigins)
+
+	e.string("ADMIN_API_KEY", &c.Auth.AdminAPIKey)
+	e.list("JWT_SECRETS", &c.Auth.JWT.Secrets)
+	e.string("JWT_JWKS_PATH", &c.Auth.JWT.JWKSPath)
+	e.string("JWT_AUDIENCE", &c.Auth.JWT.Audience)
+	e.string("JWT_TENANT", &c.Auth.JWT.Tenant)
+	e.string("JWT_ROLES_CLAIM", &c.Auth.JWT.RolesClaim)
+	e.string("JWT_TENANT_CLAIM", &c.Auth.JWT.TenantClaim)
+	e.duration("JWT_LEEWAY", &c.Auth.JWT.Leeway)
+
+	e.string("DATA_DIR", &c.Storage.DataDir)
+	e.string("LOG_STORAGE", (*string)(&c.Storage.Logs.Backend))
+	e.string("LOG_PATH", &c.Storage.Logs.Path)
+	switch c.Storage.Logs.Backend {
+	case JSONLBackend:
+		e.string("LOG_JSONL_PATH", &c.Storage.Logs.Path)
+	case SQLiteBackend:
+		e.string("LOG_SQLITE_PATH", &c.Storage.Logs.Path)
+	}
+	e.string("JOB_QUEUE_PATH", &c.Storage.JobQueuePath)
+
+	e.string("SMTP_HOST", &c.Providers.SMTP.Host)
+	e.int("SMTP_PORT", &c.Providers.SMTP.Port)
+	e.string("SMTP_USERNAME", &c.Providers.SMTP.Username)
+	e.string("SMTP_PASSWORD", &c.Providers.SMTP.Password)
+	e.string("SMTP_FROM", &c.Providers.SMTP.From)
+	e.string("SMTP_SUBJECT", &c.Providers.SMTP.Subject)
+	e.string("SMTP_TLS", &c.Providers.SMTP.TLS)
+	e.string("SMS_GATEWAY_URL", &c.Providers.SMS.GatewayURL)
+	e.string("SMS_ACCOUNT_ID", &c.Providers.SMS.AccountID)
+	e.string("SMS_AUTH_TOKEN", &c.Providers.SMS.AuthToken)
+	e.string("SMS_FROM", &c.Providers.SMS.From)
+	e.string("SMS_COUNTRY_CODE", &c.Providers.SMS.CountryCode)
+	e.string("SMS_STATUS_CALLBACK", &c.Providers.SMS.StatusCallback)
+	e.string("PUSH_ENDPOINT", &c.Providers.Push.Endpoint)
+	e.string("PUSH_ACCESS_TOKEN", &c.Providers.Push.AccessToken)
+	e.string("PUSH_TITLE", &c.Providers.Push.Title)
+
+	e.int("DISPATCH_WORKERS", &c.Workers.Dispatch)
+	e.duration("DISPATCH_BATCH_WINDOW", &c.Workers.BatchWindow)
+	e.duration("DISPATCH_LOW_PRIORITY_DELAY", &c.Workers.LowPriorityDelay)
+	e.duration("JOB_RETENTION", &c.Workers.JobRetention)
+	e.duration("SCHEDULE_INTERVAL", &c.Workers.ScheduleInterval)
+	e.duration("SCHEDULE_RETENTION", &c.Workers.ScheduleRetention)
+
+	e.int("LOG_RETENTION_DAYS", &c.Retention.Days)
+	e.duration("LOG_RETENTION_INTERVAL", &c.Retention.Interval)
+	e.duration("IDEMPOTENCY_TTL", &c.IdempotencyTTL)
+	e.duration("FALLBACK_TIMEOUT", &c.FallbackTimeout)
+	e.fallbackChains("FALLBACK_CHAINS", &c.FallbackChains)
+	for _, channel := range entity.Channels {
+		e.rateLimit(channel, &c.RateLimits)
+	}
+}
+
+func (e *envReader) string(name string, target *string) {
+	if value := e.getenv(name); value != "" {
+		*target = value
+	}
+}
+
+// list reads a comma separated list, dropping empty entries.
+func (e *envReader) list(name string, target *[]string) {
+	value := e.getenv(name)
+	if value == "" {
+		return
+	}
+
+	*target = nil
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			*target = append(*target, entry)
+		}
+	}
+}
+
+// fallbackChains reads chains written as "Finance=Push,SMS,Email;Sports=Push",
+// which replace those of the file.
+func (e *envReader) fallbackChains(name string, target *map[entity.Category][]entity.Channel) {
+	value := e.getenv(name)
+	if value == "" {
+		return
+	}
+
+	chains := map[entity.Category][]entity.Channel{}
+	for _, definition := range strings.Split(value, ";") {
+		if strings.TrimSpace(definition) == "" {
+			continue
+		}
+
+		category, channels, ok := strings.Cut(definition, "=")
+		if !ok {
+			e.problems = append(e.problems, fmt.Sprintf("%s: %q is not a chain such as Finance=Push,SMS,Email", name, definition))
+			continue
+		}
+
+		var chain []entity.Channel
+		for _, channel := range strings.Split(channels, ",") {
+			if channel = strings.TrimSpace(channel); channel != "" {
+				chain = append(chain, entity.Channel(channel))
+			}
+		}
+		chains[entity.Category(strings.TrimSpace(category))] = chain
+	}
+
+	*target = chains
+}
+
+// rateLimit reads the channel's RATE_LIMIT_<CHANNEL> for each user,
+// PROVIDER_LIMIT_<CHANNEL> for the provider and RATE_LIMIT_POLICY_<CHANNEL>,
+// written as drop, defer or downgrade:<channel>.
+func (e *envReader) rateLimit(channel entity.Channel, target *map[entity.Channel]RateLimitConfig) {
+	suffix := strings.ToUpper(string(channel))
+	limit := (*target)[channel]
+	set := e.limit("RATE_LIMIT_"+suffix, &limit.User)
+	set = e.limit("PROVIDER_LIMIT_"+suffix, &limit.Provider) || set
+	if policy := e.getenv("RATE_LIMIT_POLICY_" + suffix); policy != "" {
+		name, downgrade, _ := strings.Cut(policy, ":")
+		limit.Policy = name
+		limit.Downgrade = entity.Channel(downgrade)
+		set = true
+	}
+
+	if !set {
+		return
+	}
+
+	if *target == nil {
+		*target = map[entity.Channel]RateLimitConfig{}
+	}
+	(*target)[channel] = limit
+}
+
+// limit reports whether the variable is set, even when it can't be parsed.
+func (e *envReader) limit(name string, target *Limit) bool {
+	value := e.getenv(name)
+	if value == "" {
+		return false
+	}
+
+	if err := target.parse(value); err != nil {
+		e.problems = append(e.problems, fmt.Sprintf("%s: %v", name, err))
+	}
+
+	return true
+}
+
+func (e *envReader) int(name string, target *int) {
+	value := e.getenv(name)
+	if value == "" {
+		return
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		e.problems = append(e.problems, fmt.Sprintf("%s: %q is not a number", name, value))
+		return
+	}
+	*target = parsed
+}
+
+func (e *envReader) duration(name string, target *Duration) {
+	value := e.getenv(name)
+	if value == "" {
+		return
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		e.problems = append(e.problems, fmt.Sprintf("%s: %q is not a duration such as 30s or 1h", name, value))
+		return
+	}
+	*target = Duration(parsed)
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Validate returns every invalid setting, named by its key in the file.
+func (c Config) Validate() []string {
+	var problems []string
+	invalid := func(key string, format string, args ...interface{}) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	if _, port, err := net.SplitHostPort(c.Server.Address); err != nil {
+		invalid("server.address", "%q is not a host:port address such as :8080", c.Server.Address)
+	} else if number, err := strconv.Atoi(port); err != nil || number < 0 || number > 65535 {
+		invalid("server.address", "%q is not a valid port", port)
+	}
+	if c.Server.TLS.IsEnabled() {
+		if c.Server.TLS.CertFile == "" || c.Server.TLS.KeyFile == "" {
+			invalid("server.tls", "cert_file and key_file must be set together")
+		}
+		checkFile(invalid, "server.tls.cert_file", c.Server.TLS.CertFile)
+		checkFile(invalid, "server.tls.key_file", c.Server.TLS.KeyFile)
+	}
+	for _, origin := range c.Server.CORSAllowedOrigins {
+		if parsed, err := url.Parse(origin); err != nil || parsed.Scheme == "" || parsed.Host == "" || parsed.Path != "" {
+			invalid("server.cors_allowed_origins", "%q is not an origin such as https://app.example.com", origin)
+		}
+	}
+
+	if c.Auth.JWT.IsEnabled() && c.Auth.JWT.Audience == "" {
+		invalid("auth.jwt.audience", "is required when secrets or jwks_path is set")
+	}
+	if c.Auth.JWT.JWKSPath != "" {
+		checkFile(invalid, "auth.jwt.jwks_path", c.Auth.JWT.JWKSPath)
+	}
+	if c.Auth.JWT.Leeway < 0 {
+		invalid("auth.jwt.leeway", "must not be negative")
+	}
+
+	if c.Storage.DataDir == "" {
+		invalid("storage.data_dir", "is required, set it in the file or with DATA_DIR")
+	} else if info, err := os.Stat(c.Storage.DataDir); err != nil {
+		invalid("storage.data_dir", "%v", err)
+	} else if !info.IsDir() {
+		invalid("storage.data_dir", "%s is not a directory", c.Storage.DataDir)
+	}
+	switch c.Storage.Logs.Backend {
+	case FileBackend, JSONLBackend, SQLiteBackend:
+	default:
+		invalid("storage.logs.backend", "%q is not one of file, jsonl or sqlite", c.Storage.Logs.Backend)
+	}
+
+	smtp := c.Providers.SMTP
+	if smtp.Host != "" {
+		// Port 0 picks the TLS mode's default port.
+		if smtp.Port < 0 || smtp.Port > 65535 {
+			invalid("providers.smtp.port", "%d is not a valid port", smtp.Port)
+		}
+		if smtp.From == "" {
+			invalid("providers.smtp.from", "is required when host is set")
+		}
+	}
+	switch smtp.TLS {
+	case "", "none", "starttls", "tls":
+	default:
+		invalid("providers.smtp.tls", "%q is not one of none, starttls or tls", smtp.TLS)
+	}
+
+	sms := c.Providers.SMS
+	if sms.StatusCallback != "" {
+		checkURL(invalid, "providers.sms.status_callback", sms.StatusCallback)
+	}
+	if sms.GatewayURL != "" {
+		checkURL(invalid, "providers.sms.gateway_url", sms.GatewayURL)
+		required := []struct{ key, value string }{
+			{"account_id", sms.AccountID},
+			{"auth_token", sms.AuthToken},
+			{"from", sms.From},
+		}
+		for _, setting := range required {
+			if setting.value == "" {
+				invalid("providers.sms."+setting.key, "is required when gateway_url is set")
+			}
+		}
+	}
+
+	push := c.Providers.Push
+	if push.Endpoint != "" {
+		checkURL(invalid, "providers.push.endpoint", push.Endpoint)
+		if push.AccessToken == "" {
+			invalid("providers.push.access_token", "is required when endpoint is set")
+		}
+	}
+
+	if c.Workers.Dispatch < 1 {
+		invalid("workers.dispatch", "must be at least 1")
+	}
+	if c.Workers.LowPriorityDelay < 0 {
+		invalid("workers.low_priority_delay", "must not be negative")
+	}
+	if c.Workers.JobRetention < 0 {
+		invalid("workers.job_retention", "must not be negative")
+	}
+	if c.Workers.ScheduleInterval <= 0 {
+		invalid("workers.schedule_interval", "must be positive")
+	}
+	if c.Workers.ScheduleRetention < 0 {
+		invalid("workers.schedule_retention", "must not be negative")
+	}
+
+	if c.Retention.Days < 0 {
+		invalid("retention.days", "must not be negative")
+	}
+	if c.Retention.Interval <= 0 {
+		invalid("retention.interval", "must be positive")
+	}
+	if c.IdempotencyTTL < 0 {
+		invalid("idempotency_ttl", "must not be negative")
+	}
+	if c.FallbackTimeout < 0 {
+		invalid("fallback_timeout", "must not be negative")
+	}
+
+	for _, category := range sortedKeys(c.FallbackChains) {
+		key := "fallback_chains." + string(category)
+		if !category.IsValid() {
+			invalid(key, "%q is not a category", category)
+		}
+		if len(c.FallbackChains[category]) == 0 {
+			invalid(key, "must list at least one channel")
+		}
+		for _, channel := range c.FallbackChains[category] {
+			if !channel.IsValid() {
+				invalid(key, "%q is not a channel", channel)
+			}
+		}
+	}
+
+	for _, channel := range sortedKeys(c.RateLimits) {
+		key := "rate_limits." + string(channel)
+		limit := c.RateLimits[channel]
+		if !channel.IsValid() {
+			invalid(key, "%q is not a channel", channel)
+		}
+		if limit.User.Limit().IsZero() && limit.Provider.Limit().IsZero() {
+			invalid(key, "user or provider is required")
+		}
+		switch limit.Policy {
+		case "", "drop", "defer":
+		case "downgrade":
+			if !limit.Downgrade.IsValid() || limit.Downgrade == channel {
+				invalid(key+".downgrade", "%q is not another channel", limit.Downgrade)
+			}
+		default:
+			invalid(key+".policy", "%q is not one of drop, defer or downgrade", limit.Policy)
+		}
+	}
+
+	return problems
+}
+
+// sortedKeys returns the keys of a map in order, so that its problems are
+// always listed the same way.
+func sortedKeys[K ~string, V any](values map[K]V) []K {
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func checkFile(invalid func(string, string, ...interface{}), key string, path string) {
+	if path == "" {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		invalid(key, "%v", err)
+	}
+}
+
+func checkURL(invalid func(string, string, ...interface{}), key string, value string) {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		invalid(key, "%q is not an http or https URL", value)
+	}
+}
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"errors"
+	"notification/internal/entity"
+	"sync"
+)
+
+// APIKeyRepository reads the key file once and keeps the keys in memory, as
+// every authenticated request looks its key up. Keys saved through the
+// repository update the file and the cache together; edits made to the file
+// by hand are only seen after a restart.
+type APIKeyRepository struct {
+	apiKeyFilePath string
+	mutex          sync.Mutex
+	keys           []entity.APIKey
+}
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+type APIKey interface {
+	SaveAPIKey(key entity.APIKey) error
+	GetAPIKeys() ([]entity.APIKey, error)
+	GetAPIKey(id string) (entity.APIKey, error)
+	GetAPIKeyByHash(hash string) (entity.APIKey, error)
+}
+
+func NewAPIKeyRepository(apiKeyFilePath string) APIKey {
+	return &APIKeyRepository{
+		apiKeyFilePath: apiKeyFilePath,
+	}
+}
+
+func (r *APIKeyRepository) SaveAPIKey(key entity.APIKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys, err := r.readKeys()
+	if err != nil {
+		return err
+	}
+
+	updated := append([]entity.APIKey{}, keys...)
+	replaced := false
+	for i := range updated {
+		if updated[i].ID == key.ID {
+			updated[i] = key
+			replaced = true
+		}
+	}
+	if !replaced {
+		updated = append(updated, key)
+	}
+
+	if err := writeJSONFile(r.apiKeyFilePath, updated); err != nil {
+		return err
+	}
+
+	r.keys = updated
+	return nil
+}
+
+func (r *APIKeyRepository) GetAPIKeys() ([]entity.APIKey, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys, err := r.readKeys()
+	return append([]entity.APIKey{}, keys...), err
+}
+
+func (r *APIKeyRepository) GetAPIKey(id string) (entity.APIKey, error) {
+	return r.findKey(func(key entity.APIKey) bool { return key.ID == id })
+}
+
+func (r *APIKeyRepository) GetAPIKeyByHash(hash string) (entity.APIKey, error) {
+	return r.findKey(func(key entity.APIKey) bool { return key.Hash == hash })
+}
+
+func (r *APIKeyRepository) findKey(matches func(key entity.APIKey) bool) (entity.APIKey, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys, err := r.readKeys()
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	for _, key := range keys {
+		if matches(key) {
+			return key, nil
+		}
+	}
+
+	return entity.APIKey{}, ErrAPIKeyNotFound
+}
+
+// readKeys returns the cached keys, reading the file on first use. The
+// mutex must be held and the slice must not be modified.
+func (r *APIKeyRepository) readKeys() ([]entity.APIKey, error) {
+	if r.keys != nil {
+		return r.keys, nil
+	}
+
+	keys := []entity.APIKey{}
+	if err := readJSONFile(r.apiKeyFilePath, &keys); err != nil {
+		return nil, err
+	}
+
+	r.keys = keys
+	return keys, nil
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Success(t *testing.T) {
+	urlAPIKey := t.TempDir() + "/api-keys.json"
+
+	apiKeyRepository := NewAPIKeyRepository(urlAPIKey)
+
+	err := apiKeyRepository.SaveAPIKey(getAPIKey("first", "hash-1"))
+	assert.NoError(t, err)
+
+	err = apiKeyRepository.SaveAPIKey(getAPIKey("second", "hash-2"))
+	assert.NoError(t, err)
+
+	revoked := getAPIKey("first", "hash-1")
+	revokedAt := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	revoked.RevokedAt = &revokedAt
+	err = apiKeyRepository.SaveAPIKey(revoked)
+	assert.NoError(t, err)
+
+	keys, err := NewAPIKeyRepository(urlAPIKey).GetAPIKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(keys))
+
+	key, err := apiKeyRepository.GetAPIKey("first")
+	assert.NoError(t, err)
+	assert.True(t, key.IsRevoked())
+
+	key, err = apiKeyRepository.GetAPIKeyByHash("hash-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", key.ID)
+	assert.Equal(t, []entity.Scope{entity.ScopeReadLogs}, key.Scopes)
+}
+
+func TestAPIKey_Cache_Success(t *testing.T) {
+	urlAPIKey := t.TempDir() + "/api-keys.json"
+	assert.NoError(t, NewAPIKeyRepository(urlAPIKey).SaveAPIKey(getAPIKey("first", "hash-1")))
+
+	apiKeyRepository := NewAPIKeyRepository(urlAPIKey)
+	_, err := apiKeyRepository.GetAPIKeyByHash("hash-1")
+	assert.NoError(t, err)
+
+	// The keys are read once; changes to the file aren't seen.
+	assert.NoError(t, os.Remove(urlAPIKey))
+	_, err = apiKeyRepository.GetAPIKeyByHash("hash-1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, apiKeyRepository.SaveAPIKey(getAPIKey("second", "hash-2")))
+	keys, err := NewAPIKeyRepository(urlAPIKey).GetAPIKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(keys))
+}
+
+func TestAPIKey_NotFound_Error(t *testing.T) {
+	apiKeyRepository := NewAPIKeyRepository(t.TempDir() + "/api-keys.json")
+
+	_, err := apiKeyRepository.GetAPIKey("missing")
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+
+	_, err = apiKeyRepository.GetAPIKeyByHash("missing")
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+}
+
+func getAPIKey(id string, hash string) entity.APIKey {
+	return entity.APIKey{
+		ID:        id,
+		Name:      "reporting",
+		Hash:      hash,
+		Scopes:    []entity.Scope{entity.ScopeReadLogs},
+		CreatedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"errors"
+	"notification/internal/entity"
+	"sync"
+)
+
+type DeadLetterRepository struct {
+	deadLetterFilePath string
+	mutex              sync.Mutex
+}
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+type DeadLetter interface {
+	SaveDeadLetter(deadLetter entity.DeadLetter) error
+	GetDeadLetter(id string) (entity.DeadLetter, error)
+	GetDeadLetters() ([]entity.DeadLetter, error)
+	DeleteDeadLetter(id string) error
+}
+
+func NewDeadLetterRepository(deadLetterFilePath string) DeadLetter {
+	return &DeadLetterRepository{
+		deadLetterFilePath: deadLetterFilePath,
+	}
+}
+
+func (r *DeadLetterRepository) SaveDeadLetter(deadLetter entity.DeadLetter) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deadLetters, err := r.readDeadLetters()
+	if err != nil {
+		return err
+	}
+
+	for i := range deadLetters {
+		if deadLetters[i].ID == deadLetter.ID {
+			deadLetters[i] = deadLetter
+			return writeJSONFile(r.deadLetterFilePath, deadLetters)
+		}
+	}
+
+	deadLetters = append(deadLetters, deadLetter)
+	return writeJSONFile(r.deadLetterFilePath, deadLetters)
+}
+
+func (r *DeadLetterRepository) GetDeadLetter(id string) (entity.DeadLetter, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deadLetters, err := r.readDeadLetters()
+	if err != nil {
+		return entity.DeadLetter{}, err
+	}
+
+	for _, deadLetter := range deadLetters {
+		if deadLetter.ID == id {
+			return deadLetter, nil
+		}
+	}
+
+	return entity.DeadLetter{}, ErrDeadLetterNotFound
+}
+
+func (r *DeadLetterRepository) GetDeadLetters() ([]entity.DeadLetter, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.readDeadLetters()
+}
+
+func (r *DeadLetterRepository) DeleteDeadLetter(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deadLetters, err := r.readDeadLetters()
+	if err != nil {
+		return err
+	}
+
+	for i := range deadLetters {
+		if deadLetters[i].ID == id {
+			deadLetters = append(deadLetters[:i], deadLetters[i+1:]...)
+			return writeJSONFile(r.deadLetterFilePath, deadLetters)
+		}
+	}
+
+	return ErrDeadLetterNotFound
+}
+
+func (r *DeadLetterRepository) readDeadLetters() ([]entity.DeadLetter, error) {
+	deadLetters := []entity.DeadLetter{}
+	err := readJSONFile(r.deadLetterFilePath, &deadLetters)
+	return deadLetters, err
+}
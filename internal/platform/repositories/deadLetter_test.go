@@ -0,0 +1,60 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetter_Success(t *testing.T) {
+	urlDeadLetter := t.TempDir() + "/dead-letters.json"
+
+	deadLetterRepository := NewDeadLetterRepository(urlDeadLetter)
+
+	err := deadLetterRepository.SaveDeadLetter(getDeadLetter("1"))
+	assert.NoError(t, err)
+
+	err = deadLetterRepository.SaveDeadLetter(getDeadLetter("2"))
+	assert.NoError(t, err)
+
+	updated := getDeadLetter("1")
+	updated.Attempts = 6
+	err = deadLetterRepository.SaveDeadLetter(updated)
+	assert.NoError(t, err)
+
+	deadLetter, err := deadLetterRepository.GetDeadLetter("1")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, deadLetter.Attempts)
+	assert.Equal(t, entity.SportsCategory, deadLetter.Notification.Category)
+
+	err = deadLetterRepository.DeleteDeadLetter("1")
+	assert.NoError(t, err)
+
+	deadLetters, err := deadLetterRepository.GetDeadLetters()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(deadLetters))
+
+	_, err = deadLetterRepository.GetDeadLetter("1")
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+
+	err = deadLetterRepository.DeleteDeadLetter("1")
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+}
+
+func getDeadLetter(id string) entity.DeadLetter {
+	return entity.DeadLetter{
+		ID: id,
+		Notification: entity.Notification{
+			Message:  "test test",
+			Category: entity.SportsCategory,
+		},
+		UserID:    1,
+		Channel:   entity.SMSChannel,
+		Error:     "invalid phone number",
+		Attempts:  3,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+}
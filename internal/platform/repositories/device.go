@@ -0,0 +1,115 @@
+package log
+
+import (
+	"errors"
+	"notification/internal/entity"
+	"sync"
+)
+
+type DeviceRepository struct {
+	deviceFilePath string
+	mutex          sync.Mutex
+}
+
+var ErrDeviceNotFound = errors.New("device not found")
+
+type Device interface {
+	SaveDevice(device entity.Device) error
+	GetDevicesByUser(userID int) ([]entity.Device, error)
+	DeleteDevice(token string) error
+	DeleteDevicesByUser(userID int) error
+}
+
+func NewDeviceRepository(deviceFilePath string) Device {
+	return &DeviceRepository{
+		deviceFilePath: deviceFilePath,
+	}
+}
+
+func (r *DeviceRepository) SaveDevice(device entity.Device) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	devices, err := r.readDevices()
+	if err != nil {
+		return err
+	}
+
+	for i := range devices {
+		if devices[i].Token == device.Token {
+			devices[i] = device
+			return writeJSONFile(r.deviceFilePath, devices)
+		}
+	}
+
+	devices = append(devices, device)
+	return writeJSONFile(r.deviceFilePath, devices)
+}
+
+func (r *DeviceRepository) GetDevicesByUser(userID int) ([]entity.Device, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	devices, err := r.readDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	var userDevices []entity.Device
+	for _, device := range devices {
+		if device.UserID == userID {
+			userDevices = append(userDevices, device)
+		}
+	}
+
+	return userDevices, nil
+}
+
+func (r *DeviceRepository) DeleteDevice(token string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	devices, err := r.readDevices()
+	if err != nil {
+		return err
+	}
+
+	for i := range devices {
+		if devices[i].Token == token {
+			devices = append(devices[:i], devices[i+1:]...)
+			return writeJSONFile(r.deviceFilePath, devices)
+		}
+	}
+
+	return ErrDeviceNotFound
+}
+
+// DeleteDevicesByUser removes every device of the user, if any.
+func (r *DeviceRepository) DeleteDevicesByUser(userID int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	devices, err := r.readDevices()
+	if err != nil {
+		return err
+	}
+
+	kept := make([]entity.Device, 0, len(devices))
+	for _, device := range devices {
+		if device.UserID != userID {
+			kept = append(kept, device)
+		}
+	}
+
+	if len(kept) == len(devices) {
+		return nil
+	}
+
+	return writeJSONFile(r.deviceFilePath, kept)
+}
+
+func (r *DeviceRepository) readDevices() ([]entity.Device, error) {
+	devices := []entity.Device{}
+	err := readJSONFile(r.deviceFilePath, &devices)
+	return devices, err
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevice_Success(t *testing.T) {
+	urlDevice := t.TempDir() + "/devices.json"
+
+	deviceRepository := NewDeviceRepository(urlDevice)
+
+	err := deviceRepository.SaveDevice(getDevice("token-1", 1))
+	assert.NoError(t, err)
+
+	err = deviceRepository.SaveDevice(getDevice("token-2", 1))
+	assert.NoError(t, err)
+
+	err = deviceRepository.SaveDevice(getDevice("token-3", 2))
+	assert.NoError(t, err)
+
+	moved := getDevice("token-2", 2)
+	err = deviceRepository.SaveDevice(moved)
+	assert.NoError(t, err)
+
+	devices, err := deviceRepository.GetDevicesByUser(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(devices))
+
+	err = deviceRepository.DeleteDevice("token-1")
+	assert.NoError(t, err)
+
+	devices, err = deviceRepository.GetDevicesByUser(1)
+	assert.NoError(t, err)
+	assert.Empty(t, devices)
+
+	err = deviceRepository.DeleteDevice("token-1")
+	assert.ErrorIs(t, err, ErrDeviceNotFound)
+
+	err = deviceRepository.DeleteDevicesByUser(2)
+	assert.NoError(t, err)
+
+	devices, err = deviceRepository.GetDevicesByUser(2)
+	assert.NoError(t, err)
+	assert.Empty(t, devices)
+
+	err = deviceRepository.DeleteDevicesByUser(2)
+	assert.NoError(t, err)
+}
+
+func getDevice(token string, userID int) entity.Device {
+	return entity.Device{
+		Token:    token,
+		UserID:   userID,
+		Platform: entity.AndroidPlatform,
+		LastSeen: time.Now().UTC().Truncate(time.Second),
+	}
+}
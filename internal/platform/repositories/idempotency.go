@@ -0,0 +1,115 @@
+package log
+
+import (
+	"errors"
+	"notification/internal/entity"
+	"sync"
+	"time"
+)
+
+type IdempotencyRepository struct {
+	idempotencyFilePath string
+	mutex               sync.Mutex
+}
+
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+type Idempotency interface {
+	SaveIdempotencyKey(key entity.IdempotencyKey) error
+	GetIdempotencyKey(caller string, key string) (entity.IdempotencyKey, error)
+	DeleteIdempotencyKey(caller string, key string) error
+	DeleteExpiredIdempotencyKeys(now time.Time) (int, error)
+}
+
+func NewIdempotencyRepository(idempotencyFilePath string) Idempotency {
+	return &IdempotencyRepository{
+		idempotencyFilePath: idempotencyFilePath,
+	}
+}
+
+func (r *IdempotencyRepository) SaveIdempotencyKey(key entity.IdempotencyKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys, err := r.readKeys()
+	if err != nil {
+		return err
+	}
+
+	for i := range keys {
+		if keys[i].Caller == key.Caller && keys[i].Key == key.Key {
+			keys[i] = key
+			return writeJSONFile(r.idempotencyFilePath, keys)
+		}
+	}
+
+	keys = append(keys, key)
+	return writeJSONFile(r.idempotencyFilePath, keys)
+}
+
+func (r *IdempotencyRepository) GetIdempotencyKey(caller string, key string) (entity.IdempotencyKey, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys, err := r.readKeys()
+	if err != nil {
+		return entity.IdempotencyKey{}, err
+	}
+
+	for _, found := range keys {
+		if found.Caller == caller && found.Key == key {
+			return found, nil
+		}
+	}
+
+	return entity.IdempotencyKey{}, ErrIdempotencyKeyNotFound
+}
+
+func (r *IdempotencyRepository) DeleteIdempotencyKey(caller string, key string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys, err := r.readKeys()
+	if err != nil {
+		return err
+	}
+
+	for i := range keys {
+		if keys[i].Caller == caller && keys[i].Key == key {
+			return writeJSONFile(r.idempotencyFilePath, append(keys[:i], keys[i+1:]...))
+		}
+	}
+
+	return ErrIdempotencyKeyNotFound
+}
+
+// DeleteExpiredIdempotencyKeys removes the keys expired at now and returns
+// how many were removed.
+func (r *IdempotencyRepository) DeleteExpiredIdempotencyKeys(now time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys, err := r.readKeys()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := []entity.IdempotencyKey{}
+	for _, key := range keys {
+		if !key.IsExpired(now) {
+			kept = append(kept, key)
+		}
+	}
+
+	if len(kept) == len(keys) {
+		return 0, nil
+	}
+
+	return len(keys) - len(kept), writeJSONFile(r.idempotencyFilePath, kept)
+}
+
+func (r *IdempotencyRepository) readKeys() ([]entity.IdempotencyKey, error) {
+	keys := []entity.IdempotencyKey{}
+	err := readJSONFile(r.idempotencyFilePath, &keys)
+	return keys, err
+}
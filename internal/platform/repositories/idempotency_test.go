@@ -0,0 +1,60 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency_Success(t *testing.T) {
+	urlIdempotency := t.TempDir() + "/idempotency.json"
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+	idempotencyRepository := NewIdempotencyRepository(urlIdempotency)
+
+	err := idempotencyRepository.SaveIdempotencyKey(getIdempotencyKey("first", now.Add(time.Hour)))
+	assert.NoError(t, err)
+
+	err = idempotencyRepository.SaveIdempotencyKey(getIdempotencyKey("second", now))
+	assert.NoError(t, err)
+
+	completed := getIdempotencyKey("first", now.Add(time.Hour))
+	completed.Response = &entity.StoredResponse{StatusCode: 202, Header: map[string]string{"Location": "/jobs/1"}, Body: "{}"}
+	err = idempotencyRepository.SaveIdempotencyKey(completed)
+	assert.NoError(t, err)
+
+	key, err := NewIdempotencyRepository(urlIdempotency).GetIdempotencyKey("caller", "first")
+	assert.NoError(t, err)
+	assert.Equal(t, completed.Response, key.Response)
+
+	deleted, err := idempotencyRepository.DeleteExpiredIdempotencyKeys(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = idempotencyRepository.GetIdempotencyKey("caller", "second")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyNotFound)
+
+	_, err = idempotencyRepository.GetIdempotencyKey("other", "first")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyNotFound)
+
+	err = idempotencyRepository.DeleteIdempotencyKey("other", "first")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyNotFound)
+
+	err = idempotencyRepository.DeleteIdempotencyKey("caller", "first")
+	assert.NoError(t, err)
+
+	err = idempotencyRepository.DeleteIdempotencyKey("caller", "first")
+	assert.ErrorIs(t, err, ErrIdempotencyKeyNotFound)
+}
+
+func getIdempotencyKey(key string, expiresAt time.Time) entity.IdempotencyKey {
+	return entity.IdempotencyKey{
+		Caller:      "caller",
+		Key:         key,
+		Fingerprint: "fingerprint",
+		CreatedAt:   expiresAt.Add(-time.Hour),
+		ExpiresAt:   expiresAt,
+	}
+}
@@ -0,0 +1,151 @@
+package log
+
+import (
+	"errors"
+	"notification/internal/entity"
+	"sort"
+	"sync"
+	"time"
+)
+
+type JobRepository struct {
+	jobFilePath string
+	jobs        map[string]entity.Job
+	loaded      bool
+	mutex       sync.Mutex
+}
+
+var ErrJobNotFound = errors.New("job not found")
+
+type Job interface {
+	SaveJob(job entity.Job) error
+	GetJob(id string) (entity.Job, error)
+	GetUnfinishedJobs() ([]entity.Job, error)
+	DeleteFinishedJobs(before time.Time) (int, error)
+}
+
+// NewJobRepository keeps jobs in memory. When jobFilePath is set new jobs and
+// status changes are also written to disk so queued jobs survive a restart.
+// Progress is only kept in memory: a job interrupted by a restart is sent
+// again from the beginning anyway.
+func NewJobRepository(jobFilePath string) Job {
+	return &JobRepository{
+		jobFilePath: jobFilePath,
+		jobs:        map[string]entity.Job{},
+	}
+}
+
+func (r *JobRepository) SaveJob(job entity.Job) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	previous, ok := r.jobs[job.ID]
+	r.jobs[job.ID] = job
+	if ok && previous.Status == job.Status {
+		return nil
+	}
+
+	return r.persist()
+}
+
+func (r *JobRepository) GetJob(id string) (entity.Job, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return entity.Job{}, err
+	}
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return entity.Job{}, ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+func (r *JobRepository) GetUnfinishedJobs() ([]entity.Job, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	var jobs []entity.Job
+	for _, job := range r.jobs {
+		if !job.IsFinished() {
+			jobs = append(jobs, job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs, nil
+}
+
+// DeleteFinishedJobs removes the jobs that finished before the given time
+// and returns how many were removed.
+func (r *JobRepository) DeleteFinishedJobs(before time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.load(); err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for id, job := range r.jobs {
+		if job.IsFinished() && job.UpdatedAt.Before(before) {
+			delete(r.jobs, id)
+			deleted++
+		}
+	}
+
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	return deleted, r.persist()
+}
+
+func (r *JobRepository) load() error {
+	if r.loaded || r.jobFilePath == "" {
+		return nil
+	}
+
+	var jobs []entity.Job
+	if err := readJSONFile(r.jobFilePath, &jobs); err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		r.jobs[job.ID] = job
+	}
+
+	r.loaded = true
+	return nil
+}
+
+func (r *JobRepository) persist() error {
+	if r.jobFilePath == "" {
+		return nil
+	}
+
+	jobs := make([]entity.Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return writeJSONFile(r.jobFilePath, jobs)
+}
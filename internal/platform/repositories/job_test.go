@@ -0,0 +1,100 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_Success(t *testing.T) {
+	jobRepository := NewJobRepository("")
+
+	err := jobRepository.SaveJob(getJob("1", entity.JobQueued))
+	assert.NoError(t, err)
+
+	job, err := jobRepository.GetJob("1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.JobQueued, job.Status)
+
+	_, err = jobRepository.GetJob("2")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestJob_Disk_Success(t *testing.T) {
+	urlJob := t.TempDir() + "/jobs.json"
+
+	jobRepository := NewJobRepository(urlJob)
+	assert.NoError(t, jobRepository.SaveJob(getJob("1", entity.JobCompleted)))
+	assert.NoError(t, jobRepository.SaveJob(getJob("2", entity.JobRunning)))
+	assert.NoError(t, jobRepository.SaveJob(getJob("3", entity.JobQueued)))
+
+	reloaded := NewJobRepository(urlJob)
+	jobs, err := reloaded.GetUnfinishedJobs()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(jobs))
+	assert.Equal(t, "2", jobs[0].ID)
+	assert.Equal(t, "3", jobs[1].ID)
+
+	job, err := reloaded.GetJob("1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SportsCategory, job.Notification.Category)
+}
+
+func TestJob_Progress_Success(t *testing.T) {
+	urlJob := t.TempDir() + "/jobs.json"
+
+	jobRepository := NewJobRepository(urlJob)
+	job := getJob("1", entity.JobRunning)
+	assert.NoError(t, jobRepository.SaveJob(job))
+
+	// Progress is kept in memory only.
+	job.Processed = 5
+	assert.NoError(t, jobRepository.SaveJob(job))
+
+	saved, err := jobRepository.GetJob("1")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, saved.Processed)
+
+	saved, err = NewJobRepository(urlJob).GetJob("1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, saved.Processed)
+}
+
+func TestJob_DeleteFinished_Success(t *testing.T) {
+	urlJob := t.TempDir() + "/jobs.json"
+
+	jobRepository := NewJobRepository(urlJob)
+	assert.NoError(t, jobRepository.SaveJob(getJob("1", entity.JobCompleted)))
+	assert.NoError(t, jobRepository.SaveJob(getJob("2", entity.JobFailed)))
+	assert.NoError(t, jobRepository.SaveJob(getJob("3", entity.JobQueued)))
+
+	before, _ := time.Parse(time.RFC3339, "2023-06-01T10:00:02Z")
+	deleted, err := jobRepository.DeleteFinishedJobs(before)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	reloaded := NewJobRepository(urlJob)
+	_, err = reloaded.GetJob("1")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	for _, id := range []string{"2", "3"} {
+		_, err = reloaded.GetJob(id)
+		assert.NoError(t, err)
+	}
+}
+
+func getJob(id string, status entity.JobStatus) entity.Job {
+	createdAt, _ := time.Parse(time.RFC3339, "2023-06-01T10:00:0"+id+"Z")
+	return entity.Job{
+		ID: id,
+		Notification: entity.Notification{
+			Message:  "test test",
+			Category: entity.SportsCategory,
+		},
+		Status:    status,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// readJSONFile decodes the file at path into value. A missing or empty file
+// leaves value untouched.
+func readJSONFile(path string, value interface{}) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to open file %s: %v", path, err)
+	}
+
+	if len(content) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(content, value); err != nil {
+		return fmt.Errorf("Failed to parse file %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// writeJSONFile replaces the file at path with value through a temporary
+// file in the same directory, so that a failed write leaves it as it was.
+func writeJSONFile(path string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode file %s: %v", path, err)
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("Failed to write file %s: %v", path, err)
+	}
+	defer os.Remove(temporary.Name())
+
+	_, err = temporary.Write(content)
+	if err == nil {
+		err = temporary.Chmod(0644)
+	}
+	if err == nil {
+		err = temporary.Sync()
+	}
+	if closeErr := temporary.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temporary.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to write file %s: %v", path, err)
+	}
+
+	return nil
+}
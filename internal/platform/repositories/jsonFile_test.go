@@ -0,0 +1,38 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONFile_Write_Success(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users.json")
+	assert.NoError(t, writeJSONFile(path, []string{"first"}))
+	assert.NoError(t, writeJSONFile(path, []string{"second"}))
+
+	var values []string
+	assert.NoError(t, readJSONFile(path, &values))
+	assert.Equal(t, []string{"second"}, values)
+
+	// The temporary file is renamed over the target, so nothing else is left.
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+func TestJSONFile_Write_Error(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	assert.NoError(t, writeJSONFile(path, []string{"first"}))
+
+	// A value that can't be encoded leaves the file as it was.
+	assert.Error(t, writeJSONFile(path, []interface{}{make(chan int)}))
+	var values []string
+	assert.NoError(t, readJSONFile(path, &values))
+	assert.Equal(t, []string{"first"}, values)
+
+	assert.Error(t, writeJSONFile(filepath.Join(path, "missing", "users.json"), nil))
+}
@@ -2,49 +2,105 @@ package log
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"notification/internal/entity"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+var ErrLogNotFound = errors.New("log not found")
+
 type LogRepository struct {
 	logFilePath string
+	format      LogFormat
+	mutex       sync.Mutex
 }
 
+type LogFormat string
+
+const (
+	TextFormat      LogFormat = "text"
+	JSONLinesFormat LogFormat = "jsonl"
+)
+
 type Log interface {
 	SaveLog(log entity.Log) error
-	GetLogs() ([]entity.Log, error)
-	DeleteLogs() error
+	GetLogs(filter entity.LogFilter) ([]entity.Log, error)
+	GetLogsPage(filter entity.LogFilter) ([]entity.Log, int, error)
+	DeleteLogs(filter entity.LogFilter) (int, error)
+	UpdateLog(log entity.Log) error
 }
 
 func NewLogRepository(logFilePath string) Log {
 	return &LogRepository{
 		logFilePath: logFilePath,
+		format:      TextFormat,
+	}
+}
+
+// NewJSONLogRepository stores one versioned JSON record per line, which
+// round-trips any message content.
+func NewJSONLogRepository(logFilePath string) Log {
+	return &LogRepository{
+		logFilePath: logFilePath,
+		format:      JSONLinesFormat,
 	}
 }
 
 func (r *LogRepository) SaveLog(log entity.Log) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	file, err := os.OpenFile(r.logFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("Failed to open log file: %v", err)
 	}
 	defer file.Close()
 
-	logEntry := fmt.Sprintf("Timestamp: %s|Category: %s|Notification Type: %s|Message: %s|ID: %s|UserID: %v\n",
-		log.Timestamp.Format(time.RFC3339), log.Category, log.NotificationType, log.Message, log.ID, log.UserID)
+	logEntry, err := r.formatLogEntry(log)
+	if err != nil {
+		return err
+	}
 
-	if _, err := file.WriteString(logEntry); err != nil {
+	if _, err := file.WriteString(logEntry + "\n"); err != nil {
 		return fmt.Errorf("Failed to write log entry: %v", err)
 	}
 
 	return nil
 }
 
-func (r *LogRepository) GetLogs() ([]entity.Log, error) {
+func (r *LogRepository) GetLogs(filter entity.LogFilter) ([]entity.Log, error) {
+	logs, _, err := r.GetLogsPage(filter)
+	return logs, err
+}
+
+// GetLogsPage returns one page of the logs matching the filter, together with
+// the number of matching logs across every page, reading the file once.
+func (r *LogRepository) GetLogsPage(filter entity.LogFilter) ([]entity.Log, int, error) {
+	logs, err := r.readLogs(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		if filter.Sort == entity.SortAscending {
+			return logs[i].Timestamp.Before(logs[j].Timestamp)
+		}
+		return logs[i].Timestamp.After(logs[j].Timestamp)
+	})
+
+	return paginate(logs, filter.Offset, filter.Limit), len(logs), nil
+}
+
+// readLogs returns the logs matching the filter. An entry that can't be
+// parsed fails the read with its line number; text logs with messages that
+// span lines are fixed by the migratelogs command.
+func (r *LogRepository) readLogs(filter entity.LogFilter) ([]entity.Log, error) {
 	file, err := os.Open(r.logFilePath)
 	if err != nil {
 		err := fmt.Errorf("Failed to open log file: %v", err)
@@ -54,14 +110,15 @@ func (r *LogRepository) GetLogs() ([]entity.Log, error) {
 
 	var logs []entity.Log
 	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		logEntry := scanner.Text()
-		log, err := parseLogEntry(logEntry)
+	scanner.Buffer(make([]byte, 64*1024), maxLogEntrySize)
+	for line := 1; scanner.Scan(); line++ {
+		log, err := r.parseLogEntry(scanner.Text())
 		if err != nil {
-			fmt.Printf("Failed to parse log entry: %v", err)
-			continue
+			return nil, fmt.Errorf("Failed to parse log entry on line %d: %v", line, err)
+		}
+		if filter.Matches(log) {
+			logs = append(logs, log)
 		}
-		logs = append(logs, log)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -69,25 +126,215 @@ func (r *LogRepository) GetLogs() ([]entity.Log, error) {
 		return nil, err
 	}
 
-	sort.Slice(logs, func(i, j int) bool {
-		return logs[i].Timestamp.After(logs[j].Timestamp)
-	})
-
 	return logs, nil
 }
 
-func (r *LogRepository) DeleteLogs() error {
-	err := os.Remove(r.logFilePath)
+// DeleteLogs removes the logs matching the filter and returns how many were
+// removed. The file is rewritten without them, keeping entries that can't be
+// parsed, and removed once it is empty. A missing file has nothing to delete.
+func (r *LogRepository) DeleteLogs(filter entity.LogFilter) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	logEntries, err := r.readLogEntries()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var kept []string
+	deleted := 0
+	for _, logEntry := range logEntries {
+		log, err := r.parseLogEntry(logEntry)
+		if err == nil && filter.Matches(log) {
+			deleted++
+			continue
+		}
+		kept = append(kept, logEntry)
+	}
+
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	if err := r.writeLogEntries(kept); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// UpdateLog replaces the stored log that has the same ID by rewriting the
+// file, so every receipt costs a full read and write of the logs. The SQLite
+// backend updates the row in place instead.
+func (r *LogRepository) UpdateLog(log entity.Log) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	logEntries, err := r.readLogEntries()
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrLogNotFound
+	}
 	if err != nil {
 		return err
 	}
+
+	updated := false
+	for i, logEntry := range logEntries {
+		stored, err := r.parseLogEntry(logEntry)
+		if err != nil || stored.ID != log.ID {
+			continue
+		}
+
+		if logEntries[i], err = r.formatLogEntry(log); err != nil {
+			return err
+		}
+		updated = true
+	}
+
+	if !updated {
+		return ErrLogNotFound
+	}
+
+	return r.writeLogEntries(logEntries)
+}
+
+// readLogEntries returns the stored entries without parsing them.
+func (r *LogRepository) readLogEntries() ([]string, error) {
+	file, err := os.Open(r.logFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open log file: %v", err)
+	}
+	defer file.Close()
+
+	var logEntries []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLogEntrySize)
+	for scanner.Scan() {
+		logEntries = append(logEntries, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan log file: %v", err)
+	}
+
+	return logEntries, nil
+}
+
+// writeLogEntries replaces the file with the entries through a temporary
+// file, and removes it when there are none.
+func (r *LogRepository) writeLogEntries(logEntries []string) error {
+	if len(logEntries) == 0 {
+		return os.Remove(r.logFilePath)
+	}
+
+	temporary := r.logFilePath + ".tmp"
+	content := strings.Join(logEntries, "\n") + "\n"
+	if err := os.WriteFile(temporary, []byte(content), 0644); err != nil {
+		return fmt.Errorf("Failed to write log file: %v", err)
+	}
+
+	if err := os.Rename(temporary, r.logFilePath); err != nil {
+		os.Remove(temporary)
+		return err
+	}
+
 	return nil
 }
 
+func (r *LogRepository) formatLogEntry(log entity.Log) (string, error) {
+	if r.format == JSONLinesFormat {
+		return formatJSONLogEntry(log)
+	}
+
+	return formatTextLogEntry(log), nil
+}
+
+func (r *LogRepository) parseLogEntry(logEntry string) (entity.Log, error) {
+	if r.format == JSONLinesFormat {
+		return parseJSONLogEntry(logEntry)
+	}
+
+	return parseLogEntry(logEntry)
+}
+
+func paginate(logs []entity.Log, offset int, limit int) []entity.Log {
+	if offset >= len(logs) {
+		return []entity.Log{}
+	}
+
+	logs = logs[offset:]
+	if limit > 0 && limit < len(logs) {
+		logs = logs[:limit]
+	}
+
+	return logs
+}
+
+// textLogField keeps a field that follows the message on its line and out of
+// the "|" separators.
+var textLogField = strings.NewReplacer("|", "/", "\n", " ", "\r", " ")
+
+// textLogMessage escapes line breaks in the message, which is kept whole, so
+// that every entry stays on one line; textLogUnescape reverses it.
+var (
+	textLogMessage  = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`)
+	textLogUnescape = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\r`, "\r")
+)
+
+func formatTextLogEntry(log entity.Log) string {
+	return fmt.Sprintf("Timestamp: %s|Category: %s|Notification Type: %s|Message: %s|ID: %s|UserID: %v|Locale: %s|Status: %s|Error: %s|NotificationID: %s|History: %s|RequestedBy: %s",
+		log.Timestamp.Format(time.RFC3339), log.Category, log.NotificationType, textLogMessage.Replace(log.Message), log.ID, log.UserID, log.Locale, log.Status,
+		textLogField.Replace(log.Error), log.NotificationID, formatTextHistory(log.History), textLogField.Replace(log.RequestedBy))
+}
+
+// formatTextHistory writes the status changes as status@timestamp, separated
+// by commas.
+func formatTextHistory(history []entity.StatusChange) string {
+	changes := make([]string, len(history))
+	for i, change := range history {
+		changes[i] = string(change.Status) + "@" + change.Timestamp.Format(time.RFC3339)
+	}
+
+	return strings.Join(changes, ",")
+}
+
+func parseTextHistory(value string) ([]entity.StatusChange, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var history []entity.StatusChange
+	for _, change := range strings.Split(value, ",") {
+		status, at, ok := strings.Cut(change, "@")
+		timestamp, err := time.Parse(time.RFC3339, at)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid status change %q", change)
+		}
+		history = append(history, entity.StatusChange{Status: entity.LogStatus(status), Timestamp: timestamp})
+	}
+
+	return history, nil
+}
+
 func parseLogEntry(logEntry string) (entity.Log, error) {
 	var log entity.Log
 
-	lines := strings.Split(logEntry, "|")
+	// The message is free text and may contain "|", so it is taken from
+	// between the fields that surround it instead of splitting on "|".
+	head, rest, ok := strings.Cut(logEntry, "|Message: ")
+	idIndex := strings.LastIndex(rest, "|ID: ")
+	if !ok || idIndex < 0 {
+		return log, fmt.Errorf("invalid log entry format: %s", logEntry)
+	}
+	log.Message = textLogUnescape.Replace(rest[:idIndex])
+
+	lines := append(strings.Split(head, "|"), strings.Split(rest[idIndex+1:], "|")...)
 	for _, line := range lines {
 		parts := strings.SplitN(line, ": ", 2)
 		if len(parts) != 2 {
@@ -108,13 +355,27 @@ func parseLogEntry(logEntry string) (entity.Log, error) {
 			log.Category = entity.Category(value)
 		case "Notification Type":
 			log.NotificationType = value
-		case "Message":
-			log.Message = value
 		case "UserID":
 			userID, _ := strconv.Atoi(value)
 			log.UserID = userID
 		case "ID":
 			log.ID = value
+		case "Locale":
+			log.Locale = value
+		case "Status":
+			log.Status = entity.LogStatus(value)
+		case "Error":
+			log.Error = value
+		case "NotificationID":
+			log.NotificationID = value
+		case "History":
+			history, err := parseTextHistory(value)
+			if err != nil {
+				return log, fmt.Errorf("failed to parse history in log entry: %s", logEntry)
+			}
+			log.History = history
+		case "RequestedBy":
+			log.RequestedBy = value
 		}
 	}
 
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"notification/internal/entity"
+	"os"
+	"strings"
+)
+
+// MigrateTextLogs converts a pipe-delimited log file into JSON Lines and
+// returns the number of entries written. A line that doesn't start a new
+// entry is the continuation of a message that contained a line break. Nothing
+// is written if any entry can't be parsed.
+func MigrateTextLogs(source string, destination string) (int, error) {
+	if _, err := os.Stat(destination); err == nil {
+		return 0, fmt.Errorf("destination %s already exists", destination)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	logs, err := readTextLogs(source)
+	if err != nil {
+		return 0, err
+	}
+
+	temporary := destination + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to create %s: %v", temporary, err)
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, log := range logs {
+		logEntry, err := formatJSONLogEntry(log)
+		if err != nil {
+			file.Close()
+			os.Remove(temporary)
+			return 0, err
+		}
+		writer.WriteString(logEntry + "\n")
+	}
+
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(temporary)
+		return 0, fmt.Errorf("Failed to write %s: %v", temporary, err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(temporary)
+		return 0, err
+	}
+
+	if err := os.Rename(temporary, destination); err != nil {
+		os.Remove(temporary)
+		return 0, err
+	}
+
+	return len(logs), nil
+}
+
+func readTextLogs(source string) ([]entity.Log, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open log file: %v", err)
+	}
+	defer file.Close()
+
+	type textEntry struct {
+		line    int
+		content string
+	}
+
+	var entries []textEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLogEntrySize)
+	for line := 1; scanner.Scan(); line++ {
+		content := scanner.Text()
+		if strings.HasPrefix(content, "Timestamp: ") || len(entries) == 0 {
+			entries = append(entries, textEntry{line: line, content: content})
+			continue
+		}
+		entries[len(entries)-1].content += "\n" + content
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan log file: %v", err)
+	}
+
+	logs := make([]entity.Log, 0, len(entries))
+	for _, entry := range entries {
+		if entry.content == "" {
+			continue
+		}
+
+		log, err := parseLogEntry(entry.content)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", entry.line, err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
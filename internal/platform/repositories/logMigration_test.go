@@ -0,0 +1,50 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateTextLogs_Success(t *testing.T) {
+	directory := t.TempDir()
+	source := directory + "/logs.txt"
+	destination := directory + "/logs.jsonl"
+
+	textRepository := NewLogRepository(source)
+	first := getMessage(1, "SMS")
+	first.Message = "Score: 2|1"
+	second := getMessage(2, "E-Mail")
+	second.Message = "first line\nsecond line"
+	assert.NoError(t, textRepository.SaveLog(first))
+	assert.NoError(t, textRepository.SaveLog(second))
+
+	count, err := MigrateTextLogs(source, destination)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	logs, err := NewJSONLogRepository(destination).GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(logs))
+
+	messages := []string{logs[0].Message, logs[1].Message}
+	assert.ElementsMatch(t, []string{first.Message, second.Message}, messages)
+}
+
+func TestMigrateTextLogs_Error(t *testing.T) {
+	directory := t.TempDir()
+	source := directory + "/logs.txt"
+	destination := directory + "/logs.jsonl"
+
+	assert.NoError(t, os.WriteFile(source, []byte("Timestamp: not a timestamp\n"), 0644))
+	_, err := MigrateTextLogs(source, destination)
+	assert.Error(t, err)
+	_, err = os.Stat(destination)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, os.WriteFile(destination, nil, 0644))
+	_, err = MigrateTextLogs(source, destination)
+	assert.Error(t, err)
+}
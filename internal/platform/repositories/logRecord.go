@@ -0,0 +1,102 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"notification/internal/entity"
+	"time"
+)
+
+// logRecordVersion is written with every JSON Lines record. Bump it when the
+// record changes in a way older readers can't handle and keep decoding the
+// previous versions.
+const logRecordVersion = 1
+
+// maxLogEntrySize bounds a single stored entry, so long messages still fit.
+const maxLogEntrySize = 1024 * 1024
+
+type logRecord struct {
+	Version          int                  `json:"v"`
+	ID               string               `json:"id"`
+	NotificationID   string               `json:"notification_id,omitempty"`
+	UserID           int                  `json:"user_id"`
+	Message          string               `json:"message"`
+	Category         string               `json:"category"`
+	NotificationType string               `json:"notification_type"`
+	Locale           string               `json:"locale,omitempty"`
+	Status           string               `json:"status,omitempty"`
+	Error            string               `json:"error,omitempty"`
+	Timestamp        time.Time            `json:"timestamp"`
+	History          []statusChangeRecord `json:"history,omitempty"`
+	RequestedBy      string               `json:"requested_by,omitempty"`
+}
+
+type statusChangeRecord struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func historyRecords(history []entity.StatusChange) []statusChangeRecord {
+	var records []statusChangeRecord
+	for _, change := range history {
+		records = append(records, statusChangeRecord{Status: string(change.Status), Timestamp: change.Timestamp})
+	}
+	return records
+}
+
+func historyFromRecords(records []statusChangeRecord) []entity.StatusChange {
+	var history []entity.StatusChange
+	for _, record := range records {
+		history = append(history, entity.StatusChange{Status: entity.LogStatus(record.Status), Timestamp: record.Timestamp})
+	}
+	return history
+}
+
+func formatJSONLogEntry(log entity.Log) (string, error) {
+	content, err := json.Marshal(logRecord{
+		Version:          logRecordVersion,
+		ID:               log.ID,
+		NotificationID:   log.NotificationID,
+		UserID:           log.UserID,
+		Message:          log.Message,
+		Category:         string(log.Category),
+		NotificationType: log.NotificationType,
+		Locale:           log.Locale,
+		Status:           string(log.Status),
+		Error:            log.Error,
+		Timestamp:        log.Timestamp,
+		History:          historyRecords(log.History),
+		RequestedBy:      log.RequestedBy,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode log entry: %v", err)
+	}
+
+	return string(content), nil
+}
+
+func parseJSONLogEntry(logEntry string) (entity.Log, error) {
+	var record logRecord
+	if err := json.Unmarshal([]byte(logEntry), &record); err != nil {
+		return entity.Log{}, fmt.Errorf("invalid log record: %v", err)
+	}
+
+	if record.Version < 1 || record.Version > logRecordVersion {
+		return entity.Log{}, fmt.Errorf("unsupported log record version %d", record.Version)
+	}
+
+	return entity.Log{
+		ID:               record.ID,
+		NotificationID:   record.NotificationID,
+		UserID:           record.UserID,
+		Message:          record.Message,
+		Category:         entity.Category(record.Category),
+		NotificationType: record.NotificationType,
+		Locale:           record.Locale,
+		Status:           entity.LogStatus(record.Status),
+		Error:            record.Error,
+		Timestamp:        record.Timestamp,
+		History:          historyFromRecords(record.History),
+		RequestedBy:      record.RequestedBy,
+	}, nil
+}
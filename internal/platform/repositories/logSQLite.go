@@ -0,0 +1,322 @@
+package log
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"notification/internal/entity"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type SQLiteLogRepository struct {
+	db *sql.DB
+}
+
+// logMigrations are applied in order and recorded in schema_migrations, so
+// new schema changes must be appended and existing ones never edited.
+var logMigrations = []string{
+	`CREATE TABLE logs (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL,
+		user_id INTEGER NOT NULL,
+		message TEXT NOT NULL,
+		category TEXT NOT NULL,
+		notification_type TEXT NOT NULL,
+		timestamp INTEGER NOT NULL
+	);
+	CREATE INDEX idx_logs_timestamp ON logs (timestamp);
+	CREATE INDEX idx_logs_user_id ON logs (user_id);
+	CREATE INDEX idx_logs_category ON logs (category);`,
+	`ALTER TABLE logs ADD COLUMN locale TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE logs ADD COLUMN status TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE logs ADD COLUMN error TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE logs ADD COLUMN notification_id TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_logs_notification_id ON logs (notification_id);`,
+	`ALTER TABLE logs ADD COLUMN history TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE logs ADD COLUMN requested_by TEXT NOT NULL DEFAULT '';`,
+}
+
+func NewSQLiteLogRepository(dataSourceName string) (Log, error) {
+	db, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open log database: %v", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids "database is locked".
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db, logMigrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteLogRepository{
+		db: db,
+	}, nil
+}
+
+func (r *SQLiteLogRepository) SaveLog(log entity.Log) error {
+	return r.transaction(func(tx *sql.Tx) error {
+		history, err := formatSQLiteHistory(log.History)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT INTO logs (id, notification_id, user_id, message, category, notification_type, locale, status, error, history, requested_by, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			log.ID, log.NotificationID, log.UserID, log.Message, string(log.Category), log.NotificationType, log.Locale, string(log.Status), log.Error, history, log.RequestedBy, log.Timestamp.UnixNano())
+		if err != nil {
+			return fmt.Errorf("Failed to write log entry: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *SQLiteLogRepository) GetLogs(filter entity.LogFilter) ([]entity.Log, error) {
+	where, args := logConditions(filter)
+
+	order := "DESC"
+	if filter.Sort == entity.SortAscending {
+		order = "ASC"
+	}
+
+	query := `SELECT id, notification_id, user_id, message, category, notification_type, locale, status, error, history, requested_by, timestamp FROM logs` + where +
+		` ORDER BY timestamp ` + order + `, seq ` + order
+	if filter.Limit > 0 || filter.Offset > 0 {
+		limit := filter.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, filter.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query logs: %v", err)
+	}
+	defer rows.Close()
+
+	logs := []entity.Log{}
+	for rows.Next() {
+		var log entity.Log
+		var history string
+		var timestamp int64
+		if err := rows.Scan(&log.ID, &log.NotificationID, &log.UserID, &log.Message, &log.Category, &log.NotificationType, &log.Locale, &log.Status, &log.Error, &history, &log.RequestedBy, &timestamp); err != nil {
+			return nil, fmt.Errorf("Failed to read log entry: %v", err)
+		}
+		log.Timestamp = time.Unix(0, timestamp)
+		if log.History, err = parseSQLiteHistory(history); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read logs: %v", err)
+	}
+
+	return logs, nil
+}
+
+// GetLogsPage returns one page of the logs matching the filter, together with
+// the number of matching logs across every page.
+func (r *SQLiteLogRepository) GetLogsPage(filter entity.LogFilter) ([]entity.Log, int, error) {
+	logs, err := r.GetLogs(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where, args := logConditions(filter)
+
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM logs`+where, args...).Scan(&count); err != nil {
+		return nil, 0, fmt.Errorf("Failed to count logs: %v", err)
+	}
+
+	return logs, count, nil
+}
+
+func (r *SQLiteLogRepository) DeleteLogs(filter entity.LogFilter) (int, error) {
+	where, args := logConditions(filter)
+
+	var deleted int64
+	err := r.transaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM logs`+where, args...)
+		if err != nil {
+			return fmt.Errorf("Failed to delete logs: %v", err)
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(deleted), nil
+}
+
+func (r *SQLiteLogRepository) UpdateLog(log entity.Log) error {
+	return r.transaction(func(tx *sql.Tx) error {
+		history, err := formatSQLiteHistory(log.History)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(`UPDATE logs SET notification_id = ?, user_id = ?, message = ?, category = ?, notification_type = ?, locale = ?, status = ?, error = ?, history = ?, requested_by = ?, timestamp = ? WHERE id = ?`,
+			log.NotificationID, log.UserID, log.Message, string(log.Category), log.NotificationType, log.Locale, string(log.Status), log.Error, history, log.RequestedBy, log.Timestamp.UnixNano(), log.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to update log entry: %v", err)
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return ErrLogNotFound
+		}
+		return nil
+	})
+}
+
+// formatSQLiteHistory stores the status changes as a JSON array, or as an
+// empty string when there are none.
+func formatSQLiteHistory(history []entity.StatusChange) (string, error) {
+	if len(history) == 0 {
+		return "", nil
+	}
+
+	content, err := json.Marshal(historyRecords(history))
+	if err != nil {
+		return "", fmt.Errorf("Failed to encode log history: %v", err)
+	}
+
+	return string(content), nil
+}
+
+func parseSQLiteHistory(content string) ([]entity.StatusChange, error) {
+	if content == "" {
+		return nil, nil
+	}
+
+	var records []statusChangeRecord
+	if err := json.Unmarshal([]byte(content), &records); err != nil {
+		return nil, fmt.Errorf("Failed to read log history: %v", err)
+	}
+
+	return historyFromRecords(records), nil
+}
+
+func (r *SQLiteLogRepository) Close() error {
+	return r.db.Close()
+}
+
+// logConditions builds the WHERE clause for a filter. Search uses LIKE, which
+// SQLite matches case-insensitively for ASCII, like LogFilter.Matches.
+func logConditions(filter entity.LogFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.ID != "" {
+		conditions = append(conditions, `id = ?`)
+		args = append(args, filter.ID)
+	}
+
+	if filter.NotificationID != "" {
+		conditions = append(conditions, `notification_id = ?`)
+		args = append(args, filter.NotificationID)
+	}
+
+	if filter.UserID != 0 {
+		conditions = append(conditions, `user_id = ?`)
+		args = append(args, filter.UserID)
+	}
+
+	if filter.Category != "" {
+		conditions = append(conditions, `category = ?`)
+		args = append(args, string(filter.Category))
+	}
+
+	if filter.NotificationType != "" {
+		conditions = append(conditions, `notification_type = ?`)
+		args = append(args, filter.NotificationType)
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, `status = ?`)
+		args = append(args, string(filter.Status))
+	}
+
+	if !filter.From.IsZero() {
+		conditions = append(conditions, `timestamp >= ?`)
+		args = append(args, filter.From.UnixNano())
+	}
+
+	if !filter.To.IsZero() {
+		conditions = append(conditions, `timestamp < ?`)
+		args = append(args, filter.To.UnixNano())
+	}
+
+	if filter.Search != "" {
+		replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+		conditions = append(conditions, `message LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+replacer.Replace(filter.Search)+"%")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *SQLiteLogRepository) transaction(apply func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("Failed to start transaction: %v", err)
+	}
+
+	if err := apply(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func migrate(db *sql.DB, migrations []string) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, applied_at INTEGER NOT NULL)`); err != nil {
+		return fmt.Errorf("Failed to create schema_migrations: %v", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("Failed to read schema version: %v", err)
+	}
+
+	for i := current; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("Failed to start migration %d: %v", i+1, err)
+		}
+
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Failed to apply migration %d: %v", i+1, err)
+		}
+
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, i+1, time.Now().Unix()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("Failed to record migration %d: %v", i+1, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("Failed to commit migration %d: %v", i+1, err)
+		}
+	}
+
+	return nil
+}
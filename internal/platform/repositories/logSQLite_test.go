@@ -0,0 +1,83 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteLog_Success(t *testing.T) {
+	urlLog := t.TempDir() + "/logs.db"
+
+	logRepository, err := NewSQLiteLogRepository(urlLog)
+	assert.NoError(t, err)
+
+	older := getMessage(1, "SMS")
+	older.Timestamp = time.Now().Add(-time.Hour)
+	assert.NoError(t, logRepository.SaveLog(older))
+
+	newer := getMessage(2, "E-Mail")
+	newer.Message = "message with | and : separators"
+	assert.NoError(t, logRepository.SaveLog(newer))
+
+	logs, err := logRepository.GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, newer.ID, logs[0].ID)
+	assert.Equal(t, newer.Message, logs[0].Message)
+	assert.Equal(t, newer.Locale, logs[0].Locale)
+	assert.True(t, newer.Timestamp.Equal(logs[0].Timestamp))
+	assert.Equal(t, older.ID, logs[1].ID)
+	assert.Equal(t, entity.SportsCategory, logs[1].Category)
+
+	_, err = logRepository.DeleteLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestSQLiteLog_Filter_Success(t *testing.T) {
+	logRepository, err := NewSQLiteLogRepository(t.TempDir() + "/logs.db")
+	assert.NoError(t, err)
+
+	assertLogFilters(t, logRepository)
+}
+
+func TestSQLiteLog_Delete_Success(t *testing.T) {
+	logRepository, err := NewSQLiteLogRepository(t.TempDir() + "/logs.db")
+	assert.NoError(t, err)
+
+	assertLogDeletes(t, logRepository)
+}
+
+func TestSQLiteLog_Update_Success(t *testing.T) {
+	logRepository, err := NewSQLiteLogRepository(t.TempDir() + "/logs.db")
+	assert.NoError(t, err)
+
+	assertLogUpdates(t, logRepository)
+}
+
+func TestSQLiteLog_Migration_Success(t *testing.T) {
+	urlLog := t.TempDir() + "/logs.db"
+
+	logRepository, err := NewSQLiteLogRepository(urlLog)
+	assert.NoError(t, err)
+	assert.NoError(t, logRepository.SaveLog(getMessage(1, "SMS")))
+	assert.NoError(t, logRepository.(*SQLiteLogRepository).Close())
+
+	reopened, err := NewSQLiteLogRepository(urlLog)
+	assert.NoError(t, err)
+
+	logs, err := reopened.GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+
+	var version int
+	err = reopened.(*SQLiteLogRepository).db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version)
+	assert.NoError(t, err)
+	assert.Equal(t, len(logMigrations), version)
+}
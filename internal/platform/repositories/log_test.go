@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"notification/internal/entity"
+	"os"
 	"testing"
 	"time"
 
@@ -19,13 +20,13 @@ func TestLog_Success(t *testing.T) {
 	err := logRepository.SaveLog(log)
 	assert.NoError(t, err)
 
-	logs, err := logRepository.GetLogs()
+	logs, err := logRepository.GetLogs(entity.LogFilter{})
 	assert.Equal(t, len(logs), 1)
 
-	err = logRepository.DeleteLogs()
+	_, err = logRepository.DeleteLogs(entity.LogFilter{})
 	assert.NoError(t, err)
 
-	_, err = logRepository.GetLogs()
+	_, err = logRepository.GetLogs(entity.LogFilter{})
 	assert.Error(t, err)
 
 }
@@ -37,6 +38,268 @@ func getMessage(id int, NotificationType string) entity.Log {
 		Message:          "test test",
 		Category:         entity.SportsCategory,
 		NotificationType: NotificationType,
+		Locale:           entity.DefaultLocale,
+		Status:           entity.LogSent,
 		Timestamp:        time.Now(),
 	}
 }
+
+func TestLog_JSONLines_Success(t *testing.T) {
+	logRepository := NewJSONLogRepository(t.TempDir() + "/logs.jsonl")
+
+	log := getMessage(1, "SMS")
+	log.Message = "Score: 2|1\nCategory: sports, \"final\""
+	assert.NoError(t, logRepository.SaveLog(log))
+
+	logs, err := logRepository.GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, log.Message, logs[0].Message)
+	assert.Equal(t, log.ID, logs[0].ID)
+	assert.Equal(t, log.Locale, logs[0].Locale)
+	assert.True(t, log.Timestamp.Equal(logs[0].Timestamp))
+}
+
+func TestLog_JSONLines_Version_Error(t *testing.T) {
+	_, err := parseJSONLogEntry(`{"v":2,"id":"1-sports-SMS","user_id":1,"message":"test test"}`)
+	assert.Error(t, err)
+
+	_, err = parseJSONLogEntry(`{"id":"1-sports-SMS","user_id":1,"message":"test test"}`)
+	assert.Error(t, err)
+}
+
+func TestLog_Text_Invalid_Error(t *testing.T) {
+	logFilePath := t.TempDir() + "/logs.txt"
+	logRepository := NewLogRepository(logFilePath)
+	assert.NoError(t, logRepository.SaveLog(getMessage(1, "SMS")))
+
+	file, err := os.OpenFile(logFilePath, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	_, err = file.WriteString("continued message\n")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	_, err = logRepository.GetLogs(entity.LogFilter{})
+	assert.ErrorContains(t, err, "line 2")
+
+	_, _, err = logRepository.GetLogsPage(entity.LogFilter{})
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestLog_Text_Pipe_Success(t *testing.T) {
+	logRepository := NewLogRepository(t.TempDir() + "/logs.txt")
+
+	log := getMessage(1, "SMS")
+	log.Message = "Score: 2|1"
+	assert.NoError(t, logRepository.SaveLog(log))
+
+	logs, err := logRepository.GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, log.Message, logs[0].Message)
+	assert.Equal(t, log.ID, logs[0].ID)
+	assert.Equal(t, log.Locale, logs[0].Locale)
+}
+
+func TestLog_Text_Multiline_Success(t *testing.T) {
+	logRepository := NewLogRepository(t.TempDir() + "/logs.txt")
+
+	log := getMessage(1, "Email")
+	log.Message = "line1\nline2\r\nC:\\new\\rate"
+	assert.NoError(t, logRepository.SaveLog(log))
+	assert.NoError(t, logRepository.SaveLog(getMessage(2, "SMS")))
+
+	logs, err := logRepository.GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(logs))
+	assert.Equal(t, log.Message, logs[0].Message)
+}
+
+func TestLog_Filter_Success(t *testing.T) {
+	assertLogFilters(t, NewJSONLogRepository(t.TempDir()+"/logs.jsonl"))
+}
+
+// assertLogFilters checks that a repository filters, sorts and pages logs the
+// same way regardless of the storage behind it.
+func assertLogFilters(t *testing.T, logRepository Log) {
+	start := time.Now().Truncate(time.Second)
+	for i := 1; i <= 5; i++ {
+		log := getMessage(i%2+1, "SMS")
+		log.ID = fmt.Sprintf("log-%d", i)
+		log.Message = fmt.Sprintf("Match %d of 5", i)
+		log.Timestamp = start.Add(time.Duration(i) * time.Minute)
+		log.NotificationID = "batch-1"
+		log.RequestedBy = "billing-service"
+		if i > 2 {
+			log.NotificationID = "batch-2"
+		}
+		if i == 3 {
+			log.Status = entity.LogFailed
+			log.Error = "gateway unavailable"
+		}
+		if i == 4 {
+			log.Status = entity.LogDeferred
+		}
+		if i == 5 {
+			log.Category = entity.FinanceCategory
+			log.NotificationType = "E-Mail"
+		}
+		assert.NoError(t, logRepository.SaveLog(log))
+	}
+
+	logs, err := logRepository.GetLogs(entity.LogFilter{UserID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-5", "log-3", "log-1"}, logIDs(logs))
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{Category: entity.FinanceCategory, NotificationType: "E-Mail"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-5"}, logIDs(logs))
+
+	filter := entity.LogFilter{From: start.Add(2 * time.Minute), To: start.Add(4 * time.Minute), Sort: entity.SortAscending}
+	logs, err = logRepository.GetLogs(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-2", "log-3"}, logIDs(logs))
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{Search: "MATCH 4"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-4"}, logIDs(logs))
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{Status: entity.LogDeferred})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-4"}, logIDs(logs))
+	assert.Equal(t, entity.LogDeferred, logs[0].Status)
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{Status: entity.LogFailed})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-3"}, logIDs(logs))
+	assert.Equal(t, "gateway unavailable", logs[0].Error)
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{NotificationID: "batch-1", Sort: entity.SortAscending})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-1", "log-2"}, logIDs(logs))
+	assert.Equal(t, "batch-1", logs[0].NotificationID)
+	assert.Equal(t, "billing-service", logs[0].RequestedBy)
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{Offset: 1, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-4", "log-3"}, logIDs(logs))
+
+	logs, count, err := logRepository.GetLogsPage(entity.LogFilter{UserID: 1, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, 2, count)
+}
+
+func logIDs(logs []entity.Log) []string {
+	ids := []string{}
+	for _, log := range logs {
+		ids = append(ids, log.ID)
+	}
+	return ids
+}
+
+func TestLog_Delete_Success(t *testing.T) {
+	assertLogDeletes(t, NewLogRepository(t.TempDir()+"/logs.txt"))
+}
+
+func TestLog_Delete_Missing_Success(t *testing.T) {
+	logRepository := NewLogRepository(t.TempDir() + "/logs.txt")
+
+	deleted, err := logRepository.DeleteLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
+
+func TestLog_Update_Success(t *testing.T) {
+	assertLogUpdates(t, NewLogRepository(t.TempDir()+"/logs.txt"))
+	assertLogUpdates(t, NewJSONLogRepository(t.TempDir()+"/logs.jsonl"))
+}
+
+func TestLog_Update_Missing_Error(t *testing.T) {
+	logRepository := NewLogRepository(t.TempDir() + "/logs.txt")
+
+	err := logRepository.UpdateLog(getMessage(1, "SMS"))
+	assert.ErrorIs(t, err, ErrLogNotFound)
+}
+
+// assertLogUpdates checks that an update replaces only the log with the same
+// ID and that its status history round-trips.
+func assertLogUpdates(t *testing.T, logRepository Log) {
+	start := time.Now().Truncate(time.Second)
+	for i := 1; i <= 3; i++ {
+		log := getMessage(i, "SMS")
+		log.ID = fmt.Sprintf("log-%d", i)
+		log.Timestamp = start.Add(time.Duration(i) * time.Minute)
+		assert.NoError(t, logRepository.SaveLog(log))
+	}
+
+	logs, err := logRepository.GetLogs(entity.LogFilter{ID: "log-2"})
+	assert.NoError(t, err)
+	updated, ok := logs[0].Transition(entity.LogBounced, start.Add(time.Hour))
+	assert.True(t, ok)
+	updated.Error = "mailbox full"
+	assert.NoError(t, logRepository.UpdateLog(updated))
+
+	logs, err = logRepository.GetLogs(entity.LogFilter{Sort: entity.SortAscending})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-1", "log-2", "log-3"}, logIDs(logs))
+	assert.Equal(t, entity.LogSent, logs[0].Status)
+	assert.Empty(t, logs[0].History)
+	assert.Equal(t, entity.LogBounced, logs[1].Status)
+	assert.Equal(t, "mailbox full", logs[1].Error)
+	assert.Equal(t, 2, len(logs[1].History))
+	assert.Equal(t, entity.LogSent, logs[1].History[0].Status)
+	assert.True(t, start.Add(2*time.Minute).Equal(logs[1].History[0].Timestamp))
+	assert.Equal(t, entity.LogBounced, logs[1].History[1].Status)
+	assert.True(t, start.Add(time.Hour).Equal(logs[1].History[1].Timestamp))
+
+	missing := getMessage(4, "SMS")
+	missing.ID = "log-4"
+	assert.ErrorIs(t, logRepository.UpdateLog(missing), ErrLogNotFound)
+}
+
+// assertLogDeletes checks selective deletion by ID, user, category and age.
+func assertLogDeletes(t *testing.T, logRepository Log) {
+	start := time.Now().Truncate(time.Second)
+	for i := 1; i <= 6; i++ {
+		log := getMessage(i%3+1, "SMS")
+		log.ID = fmt.Sprintf("log-%d", i)
+		log.Timestamp = start.Add(time.Duration(i) * time.Hour)
+		if i%2 == 0 {
+			log.Category = entity.MoviesCategory
+		}
+		assert.NoError(t, logRepository.SaveLog(log))
+	}
+
+	deleted, err := logRepository.DeleteLogs(entity.LogFilter{ID: "log-6"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = logRepository.DeleteLogs(entity.LogFilter{To: start.Add(2 * time.Hour)})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = logRepository.DeleteLogs(entity.LogFilter{UserID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = logRepository.DeleteLogs(entity.LogFilter{Category: entity.MoviesCategory})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = logRepository.DeleteLogs(entity.LogFilter{UserID: 9})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+
+	logs, err := logRepository.GetLogs(entity.LogFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-5", "log-3"}, logIDs(logs))
+}
+
+func TestLog_Text_Without_Locale_Success(t *testing.T) {
+	log, err := parseLogEntry("Timestamp: 2024-01-01T10:00:00Z|Category: Sports|Notification Type: SMS|Message: test test|ID: 1-Sports-SMS|UserID: 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "test test", log.Message)
+	assert.Equal(t, 1, log.UserID)
+	assert.Equal(t, "", log.Locale)
+}
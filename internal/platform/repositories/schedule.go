@@ -0,0 +1,137 @@
+package log
+
+import (
+	"errors"
+	"notification/internal/entity"
+	"sync"
+	"time"
+)
+
+type ScheduleRepository struct {
+	scheduleFilePath string
+	mutex            sync.Mutex
+}
+
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+type Schedule interface {
+	SaveSchedule(schedule entity.Schedule) error
+	GetSchedule(id string) (entity.Schedule, error)
+	GetSchedules() ([]entity.Schedule, error)
+	SaveSchedules(schedules []entity.Schedule) error
+	DeleteFinishedSchedules(before time.Time) (int, error)
+}
+
+func NewScheduleRepository(scheduleFilePath string) Schedule {
+	return &ScheduleRepository{
+		scheduleFilePath: scheduleFilePath,
+	}
+}
+
+func (r *ScheduleRepository) SaveSchedule(schedule entity.Schedule) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	schedules, err := r.readSchedules()
+	if err != nil {
+		return err
+	}
+
+	for i := range schedules {
+		if schedules[i].ID == schedule.ID {
+			schedules[i] = schedule
+			return writeJSONFile(r.scheduleFilePath, schedules)
+		}
+	}
+
+	schedules = append(schedules, schedule)
+	return writeJSONFile(r.scheduleFilePath, schedules)
+}
+
+func (r *ScheduleRepository) GetSchedule(id string) (entity.Schedule, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	schedules, err := r.readSchedules()
+	if err != nil {
+		return entity.Schedule{}, err
+	}
+
+	for _, schedule := range schedules {
+		if schedule.ID == id {
+			return schedule, nil
+		}
+	}
+
+	return entity.Schedule{}, ErrScheduleNotFound
+}
+
+func (r *ScheduleRepository) GetSchedules() ([]entity.Schedule, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.readSchedules()
+}
+
+// SaveSchedules saves several schedules with a single write of the file.
+func (r *ScheduleRepository) SaveSchedules(updates []entity.Schedule) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	schedules, err := r.readSchedules()
+	if err != nil {
+		return err
+	}
+
+	positions := map[string]int{}
+	for i, schedule := range schedules {
+		positions[schedule.ID] = i
+	}
+
+	for _, schedule := range updates {
+		if i, ok := positions[schedule.ID]; ok {
+			schedules[i] = schedule
+			continue
+		}
+		positions[schedule.ID] = len(schedules)
+		schedules = append(schedules, schedule)
+	}
+
+	return writeJSONFile(r.scheduleFilePath, schedules)
+}
+
+// DeleteFinishedSchedules removes the completed and cancelled schedules last
+// updated before the given time and returns how many were removed.
+func (r *ScheduleRepository) DeleteFinishedSchedules(before time.Time) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	schedules, err := r.readSchedules()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := []entity.Schedule{}
+	for _, schedule := range schedules {
+		if !schedule.IsFinished() || !schedule.UpdatedAt.Before(before) {
+			kept = append(kept, schedule)
+		}
+	}
+
+	deleted := len(schedules) - len(kept)
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	return deleted, writeJSONFile(r.scheduleFilePath, kept)
+}
+
+func (r *ScheduleRepository) readSchedules() ([]entity.Schedule, error) {
+	schedules := []entity.Schedule{}
+	err := readJSONFile(r.scheduleFilePath, &schedules)
+	return schedules, err
+}
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Success(t *testing.T) {
+	urlSchedule := t.TempDir() + "/schedules.json"
+
+	scheduleRepository := NewScheduleRepository(urlSchedule)
+
+	err := scheduleRepository.SaveSchedule(getSchedule("1"))
+	assert.NoError(t, err)
+
+	err = scheduleRepository.SaveSchedule(getSchedule("2"))
+	assert.NoError(t, err)
+
+	updated := getSchedule("1")
+	updated.Status = entity.ScheduleCancelled
+	err = scheduleRepository.SaveSchedule(updated)
+	assert.NoError(t, err)
+
+	schedule, err := NewScheduleRepository(urlSchedule).GetSchedule("1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ScheduleCancelled, schedule.Status)
+	assert.Equal(t, "0 8 * * *", schedule.Cron)
+
+	schedules, err := scheduleRepository.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(schedules))
+
+	_, err = scheduleRepository.GetSchedule("3")
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+}
+
+func TestSchedule_SaveAndDelete_Success(t *testing.T) {
+	scheduleRepository := NewScheduleRepository(t.TempDir() + "/schedules.json")
+
+	now := time.Now()
+	old := getSchedule("old")
+	old.Status = entity.ScheduleCompleted
+	old.UpdatedAt = now.Add(-48 * time.Hour)
+	recent := getSchedule("recent")
+	recent.Status = entity.ScheduleCancelled
+	recent.UpdatedAt = now
+	active := getSchedule("active")
+	active.UpdatedAt = now.Add(-48 * time.Hour)
+
+	assert.NoError(t, scheduleRepository.SaveSchedule(active))
+	active.Runs = 2
+	assert.NoError(t, scheduleRepository.SaveSchedules([]entity.Schedule{old, recent, active}))
+
+	schedules, err := scheduleRepository.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(schedules))
+	assert.Equal(t, 2, schedules[0].Runs)
+
+	deleted, err := scheduleRepository.DeleteFinishedSchedules(now.Add(-24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = scheduleRepository.GetSchedule("old")
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+
+	schedules, err = scheduleRepository.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(schedules))
+}
+
+func getSchedule(id string) entity.Schedule {
+	return entity.Schedule{
+		ID: id,
+		Notification: entity.Notification{
+			Message:  "Market summary",
+			Category: entity.FinanceCategory,
+		},
+		Cron:    "0 8 * * *",
+		Status:  entity.ScheduleActive,
+		NextRun: time.Now().Add(time.Hour),
+	}
+}
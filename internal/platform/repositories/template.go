@@ -0,0 +1,106 @@
+package log
+
+import (
+	"errors"
+	"notification/internal/entity"
+	"sort"
+	"sync"
+)
+
+type TemplateRepository struct {
+	templateFilePath string
+	mutex            sync.Mutex
+}
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+type Template interface {
+	SaveTemplate(template entity.Template) error
+	GetTemplate(id string) (entity.Template, error)
+	GetTemplates() ([]entity.Template, error)
+	DeleteTemplate(id string) error
+}
+
+func NewTemplateRepository(templateFilePath string) Template {
+	return &TemplateRepository{
+		templateFilePath: templateFilePath,
+	}
+}
+
+func (r *TemplateRepository) SaveTemplate(template entity.Template) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	templates, err := r.readTemplates()
+	if err != nil {
+		return err
+	}
+
+	for i := range templates {
+		if templates[i].ID == template.ID {
+			templates[i] = template
+			return r.writeTemplates(templates)
+		}
+	}
+
+	templates = append(templates, template)
+	return r.writeTemplates(templates)
+}
+
+func (r *TemplateRepository) GetTemplate(id string) (entity.Template, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	templates, err := r.readTemplates()
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	for _, template := range templates {
+		if template.ID == id {
+			return template, nil
+		}
+	}
+
+	return entity.Template{}, ErrTemplateNotFound
+}
+
+func (r *TemplateRepository) GetTemplates() ([]entity.Template, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.readTemplates()
+}
+
+func (r *TemplateRepository) DeleteTemplate(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	templates, err := r.readTemplates()
+	if err != nil {
+		return err
+	}
+
+	for i := range templates {
+		if templates[i].ID == id {
+			templates = append(templates[:i], templates[i+1:]...)
+			return r.writeTemplates(templates)
+		}
+	}
+
+	return ErrTemplateNotFound
+}
+
+func (r *TemplateRepository) readTemplates() ([]entity.Template, error) {
+	templates := []entity.Template{}
+	err := readJSONFile(r.templateFilePath, &templates)
+	return templates, err
+}
+
+func (r *TemplateRepository) writeTemplates(templates []entity.Template) error {
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+
+	return writeJSONFile(r.templateFilePath, templates)
+}
@@ -0,0 +1,54 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate_Success(t *testing.T) {
+	urlTemplate := t.TempDir() + "/templates.json"
+
+	templateRepository := NewTemplateRepository(urlTemplate)
+
+	err := templateRepository.SaveTemplate(getTemplate("welcome"))
+	assert.NoError(t, err)
+
+	err = templateRepository.SaveTemplate(getTemplate("goal"))
+	assert.NoError(t, err)
+
+	updated := getTemplate("welcome")
+	updated.SMS.Body = "Hi {{.User.Name}}"
+	err = templateRepository.SaveTemplate(updated)
+	assert.NoError(t, err)
+
+	template, err := templateRepository.GetTemplate("welcome")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi {{.User.Name}}", template.SMS.Body)
+
+	templates, err := templateRepository.GetTemplates()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(templates))
+	assert.Equal(t, "goal", templates[0].ID)
+
+	err = templateRepository.DeleteTemplate("welcome")
+	assert.NoError(t, err)
+
+	_, err = templateRepository.GetTemplate("welcome")
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+
+	err = templateRepository.DeleteTemplate("welcome")
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
+
+func getTemplate(id string) entity.Template {
+	return entity.Template{
+		ID:   id,
+		Body: "Hello {{.User.Name}}, {{.Message}}",
+		Email: entity.EmailTemplate{
+			Subject: "News about {{.Category}}",
+			HTML:    "<p>Hello {{.User.Name}}</p>",
+		},
+	}
+}
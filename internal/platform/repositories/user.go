@@ -0,0 +1,194 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"notification/internal/entity"
+	"sort"
+	"sync"
+)
+
+type UserRepository struct {
+	userFilePath string
+	mutex        sync.Mutex
+}
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("user already exists")
+)
+
+type User interface {
+	CreateUser(user entity.User) (entity.User, error)
+	UpdateUser(user entity.User) error
+	GetUser(id int) (entity.User, error)
+	GetUsers() ([]entity.User, error)
+	GetUsersByCategory(category entity.Category) ([]entity.User, error)
+	DeleteUser(id int) error
+}
+
+func NewUserRepository(userFilePath string) User {
+	return &UserRepository{
+		userFilePath: userFilePath,
+	}
+}
+
+// userFile is the content of the users file. LastID is the highest ID ever
+// given out, so that the ID of a deleted user isn't given to another one.
+// Files written before it existed hold only the array of users.
+type userFile struct {
+	LastID int
+	Users  []entity.User
+}
+
+// CreateUser gives a user without an ID the one after the highest ever
+// given out.
+func (r *UserRepository) CreateUser(user entity.User) (entity.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	file, err := r.readFile()
+	if err != nil {
+		return user, err
+	}
+
+	for _, existing := range file.Users {
+		if user.ID != 0 && existing.ID == user.ID {
+			return user, ErrUserAlreadyExists
+		}
+	}
+
+	if user.ID == 0 {
+		user.ID = file.LastID + 1
+	}
+	if user.ID > file.LastID {
+		file.LastID = user.ID
+	}
+
+	file.Users = append(file.Users, user)
+	return user, r.writeFile(file)
+}
+
+func (r *UserRepository) UpdateUser(user entity.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	file, err := r.readFile()
+	if err != nil {
+		return err
+	}
+
+	for i := range file.Users {
+		if file.Users[i].ID == user.ID {
+			file.Users[i] = user
+			return r.writeFile(file)
+		}
+	}
+
+	return ErrUserNotFound
+}
+
+func (r *UserRepository) GetUser(id int) (entity.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	users, err := r.readUsers()
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	for _, user := range users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+
+	return entity.User{}, ErrUserNotFound
+}
+
+func (r *UserRepository) GetUsers() ([]entity.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.readUsers()
+}
+
+func (r *UserRepository) GetUsersByCategory(category entity.Category) ([]entity.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	users, err := r.readUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribers []entity.User
+	for _, user := range users {
+		if user.IsSubscribed(category) {
+			subscribers = append(subscribers, user)
+		}
+	}
+
+	return subscribers, nil
+}
+
+func (r *UserRepository) DeleteUser(id int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	file, err := r.readFile()
+	if err != nil {
+		return err
+	}
+
+	for i := range file.Users {
+		if file.Users[i].ID == id {
+			file.Users = append(file.Users[:i], file.Users[i+1:]...)
+			return r.writeFile(file)
+		}
+	}
+
+	return ErrUserNotFound
+}
+
+func (r *UserRepository) readUsers() ([]entity.User, error) {
+	file, err := r.readFile()
+	return file.Users, err
+}
+
+func (r *UserRepository) readFile() (userFile, error) {
+	file := userFile{Users: []entity.User{}}
+	var content json.RawMessage
+	if err := readJSONFile(r.userFilePath, &content); err != nil || len(content) == 0 {
+		return file, err
+	}
+
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("[")) {
+		err = json.Unmarshal(content, &file.Users)
+	} else {
+		err = json.Unmarshal(content, &file)
+	}
+	if err != nil {
+		return file, fmt.Errorf("Failed to parse file %s: %v", r.userFilePath, err)
+	}
+
+	// Users added to the file by hand may be past LastID.
+	for _, user := range file.Users {
+		if user.ID > file.LastID {
+			file.LastID = user.ID
+		}
+	}
+
+	return file, nil
+}
+
+func (r *UserRepository) writeFile(file userFile) error {
+	sort.Slice(file.Users, func(i, j int) bool {
+		return file.Users[i].ID < file.Users[j].ID
+	})
+
+	return writeJSONFile(r.userFilePath, file)
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"notification/internal/entity"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_Success(t *testing.T) {
+	urlUser := t.TempDir() + "/users.json"
+
+	userRepository := NewUserRepository(urlUser)
+
+	users, err := userRepository.GetUsers()
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+
+	created, err := userRepository.CreateUser(getUser(0, entity.SportsCategory))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+
+	_, err = userRepository.CreateUser(getUser(5, entity.FinanceCategory))
+	assert.NoError(t, err)
+
+	users, err = userRepository.GetUsersByCategory(entity.SportsCategory)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, 1, users[0].ID)
+	assert.Equal(t, []entity.Channel{entity.SMSChannel}, users[0].Channels)
+
+	err = userRepository.UpdateUser(getUser(1, entity.FinanceCategory))
+	assert.NoError(t, err)
+
+	users, err = userRepository.GetUsersByCategory(entity.FinanceCategory)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(users))
+
+	user, err := userRepository.GetUser(5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, user.ID)
+
+	err = userRepository.DeleteUser(5)
+	assert.NoError(t, err)
+
+	users, err = userRepository.GetUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(users))
+}
+
+func TestUser_ID_Success(t *testing.T) {
+	urlUser := t.TempDir() + "/users.json"
+	assert.NoError(t, os.WriteFile(urlUser, []byte(`[{"ID": 1, "Name": "Mary"}, {"ID": 2, "Name": "Antony"}]`), 0644))
+
+	userRepository := NewUserRepository(urlUser)
+	users, err := userRepository.GetUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(users))
+
+	// The ID of a deleted user isn't given out again, even after a restart.
+	assert.NoError(t, userRepository.DeleteUser(2))
+	created, err := NewUserRepository(urlUser).CreateUser(getUser(0, entity.SportsCategory))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, created.ID)
+
+	assert.NoError(t, userRepository.DeleteUser(3))
+	created, err = NewUserRepository(urlUser).CreateUser(getUser(0, entity.SportsCategory))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, created.ID)
+}
+
+func TestUser_Error(t *testing.T) {
+	urlUser := t.TempDir() + "/users.json"
+
+	userRepository := NewUserRepository(urlUser)
+
+	_, err := userRepository.CreateUser(getUser(1, entity.SportsCategory))
+	assert.NoError(t, err)
+
+	_, err = userRepository.CreateUser(getUser(1, entity.SportsCategory))
+	assert.ErrorIs(t, err, ErrUserAlreadyExists)
+
+	_, err = userRepository.GetUser(2)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	err = userRepository.UpdateUser(getUser(2, entity.SportsCategory))
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	err = userRepository.DeleteUser(2)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func getUser(id int, category entity.Category) entity.User {
+	return entity.User{
+		ID:          id,
+		Name:        "Mary Alexander",
+		Email:       "mary.alexander@outlook.com",
+		PhoneNumber: "78958745",
+		Subscribed:  []entity.Category{category},
+		Channels:    []entity.Channel{entity.SMSChannel},
+	}
+}
@@ -0,0 +1,127 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// keyPrefix starts every issued key, which makes leaked keys easy to spot.
+const keyPrefix = "nk_"
+
+var ErrInvalidKey = errors.New("invalid api key")
+
+type ValidationError struct {
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+type APIKeyUseCase struct {
+	APIKeyRepository log.APIKey
+
+	adminHash string
+	now       func() time.Time
+}
+
+// NewAPIKeyUseCase manages the keys stored in the repository. The admin key,
+// when given, is accepted with every scope, so that the first keys can be
+// issued.
+func NewAPIKeyUseCase(apiKey log.APIKey, adminKey string) *APIKeyUseCase {
+	u := &APIKeyUseCase{
+		APIKeyRepository: apiKey,
+		now:              time.Now,
+	}
+	if adminKey != "" {
+		u.adminHash = hashKey(adminKey)
+	}
+
+	return u
+}
+
+// IssueKey creates a key with the scopes and returns it together with the
+// secret, which isn't stored and can't be shown again.
+func (u *APIKeyUseCase) IssueKey(name string, scopes []entity.Scope) (entity.APIKey, string, error) {
+	if name == "" {
+		return entity.APIKey{}, "", ValidationError{Message: "name is required"}
+	}
+
+	if len(scopes) == 0 {
+		return entity.APIKey{}, "", ValidationError{Message: "at least one scope is required"}
+	}
+
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return entity.APIKey{}, "", ValidationError{Message: fmt.Sprintf("invalid scope: %s", scope)}
+		}
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return entity.APIKey{}, "", err
+	}
+	secret := keyPrefix + hex.EncodeToString(random)
+
+	key := entity.APIKey{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Prefix:    secret[:len(keyPrefix)+8],
+		Hash:      hashKey(secret),
+		Scopes:    scopes,
+		CreatedAt: u.now(),
+	}
+
+	return key, secret, u.APIKeyRepository.SaveAPIKey(key)
+}
+
+func (u *APIKeyUseCase) GetKeys() ([]entity.APIKey, error) {
+	return u.APIKeyRepository.GetAPIKeys()
+}
+
+// RevokeKey stops the key from being accepted. It is kept, so that listings
+// still show when it was revoked.
+func (u *APIKeyUseCase) RevokeKey(id string) (entity.APIKey, error) {
+	key, err := u.APIKeyRepository.GetAPIKey(id)
+	if err != nil || key.IsRevoked() {
+		return key, err
+	}
+
+	now := u.now()
+	key.RevokedAt = &now
+	return key, u.APIKeyRepository.SaveAPIKey(key)
+}
+
+// Authenticate returns the key the secret belongs to. Unknown and revoked
+// keys are reported as ErrInvalidKey.
+func (u *APIKeyUseCase) Authenticate(secret string) (entity.APIKey, error) {
+	if secret == "" {
+		return entity.APIKey{}, ErrInvalidKey
+	}
+
+	hash := hashKey(secret)
+	if u.adminHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(u.adminHash)) == 1 {
+		return entity.APIKey{ID: "admin", Name: "admin", Scopes: entity.Scopes}, nil
+	}
+
+	key, err := u.APIKeyRepository.GetAPIKeyByHash(hash)
+	if errors.Is(err, log.ErrAPIKeyNotFound) || (err == nil && key.IsRevoked()) {
+		return entity.APIKey{}, ErrInvalidKey
+	}
+
+	return key, err
+}
+
+func hashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
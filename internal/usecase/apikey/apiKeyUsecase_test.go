@@ -0,0 +1,72 @@
+package apikey
+
+import (
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Success(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	repository := repositories.NewAPIKeyRepository(t.TempDir() + "/api-keys.json")
+	service := NewAPIKeyUseCase(repository, "")
+	service.now = func() time.Time { return now }
+
+	issued, secret, err := service.IssueKey("reporting", []entity.Scope{entity.ScopeReadLogs})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "nk_"))
+	assert.True(t, strings.HasPrefix(secret, issued.Prefix))
+	assert.Equal(t, now, issued.CreatedAt)
+
+	stored, err := repository.GetAPIKey(issued.ID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, stored.Hash)
+	assert.Equal(t, 64, len(stored.Hash))
+
+	key, err := service.Authenticate(secret)
+	assert.NoError(t, err)
+	assert.Equal(t, issued.ID, key.ID)
+	assert.True(t, key.HasScope(entity.ScopeReadLogs))
+	assert.False(t, key.HasScope(entity.ScopeDeleteLogs))
+
+	revoked, err := service.RevokeKey(issued.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, now, *revoked.RevokedAt)
+
+	_, err = service.Authenticate(secret)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestAPIKey_Admin_Success(t *testing.T) {
+	service := NewAPIKeyUseCase(repositories.NewAPIKeyRepository(t.TempDir()+"/api-keys.json"), "root-secret")
+
+	key, err := service.Authenticate("root-secret")
+	assert.NoError(t, err)
+	assert.True(t, key.HasScope(entity.ScopeManageKeys))
+
+	_, err = service.Authenticate("other-secret")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = service.Authenticate("")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestAPIKey_Issue_Error(t *testing.T) {
+	service := NewAPIKeyUseCase(repositories.NewAPIKeyRepository(t.TempDir()+"/api-keys.json"), "")
+
+	_, _, err := service.IssueKey("", []entity.Scope{entity.ScopeReadLogs})
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	_, _, err = service.IssueKey("reporting", nil)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	_, _, err = service.IssueKey("reporting", []entity.Scope{"logs:write"})
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	_, err = service.RevokeKey("missing")
+	assert.ErrorIs(t, err, repositories.ErrAPIKeyNotFound)
+}
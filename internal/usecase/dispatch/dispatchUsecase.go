@@ -0,0 +1,296 @@
+package dispatch
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/notification"
+	"sync"
+	"time"
+)
+
+var ErrStopped = errors.New("dispatcher stopped")
+
+// DispatchUseCase sends queued notifications with a pool of workers, higher
+// priorities first. With a LowPriorityDelay, low priority jobs are held back
+// and queued once the delay since the first of them has passed; they are
+// still sent one by one, not merged into a digest.
+// Finished jobs are removed once they are older than JobRetention, unless it
+// is zero.
+type DispatchUseCase struct {
+	NotificationUseCase *notification.NotificationUseCase
+	JobRepository       log.Job
+	Workers             int
+	LowPriorityDelay    time.Duration
+	JobRetention        time.Duration
+
+	queue    jobQueue
+	sequence int
+	held     []entity.Job
+	delay    *time.Timer
+	stopped  bool
+	mutex    sync.Mutex
+	ready    *sync.Cond
+	workers  sync.WaitGroup
+}
+
+func NewDispatchUseCase(notification *notification.NotificationUseCase, job log.Job, workers int) *DispatchUseCase {
+	if workers < 1 {
+		workers = 1
+	}
+
+	dispatch := &DispatchUseCase{
+		NotificationUseCase: notification,
+		JobRepository:       job,
+		Workers:             workers,
+	}
+	dispatch.ready = sync.NewCond(&dispatch.mutex)
+
+	return dispatch
+}
+
+// Start re-queues the jobs left unfinished by a previous run and starts the
+// worker pool. Jobs that were running when the process stopped are sent
+// again from the beginning.
+func (d *DispatchUseCase) Start() error {
+	jobs, err := d.JobRepository.GetUnfinishedJobs()
+	if err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	for _, job := range jobs {
+		job.Status = entity.JobQueued
+		d.push(job)
+	}
+	d.mutex.Unlock()
+
+	for i := 0; i < d.Workers; i++ {
+		d.workers.Add(1)
+		go d.work()
+	}
+
+	return nil
+}
+
+// Stop waits for the running jobs to finish. Jobs still queued or held back
+// stay in the job repository and are picked up again by the next Start.
+func (d *DispatchUseCase) Stop() {
+	d.mutex.Lock()
+	d.stopped = true
+	if d.delay != nil {
+		d.delay.Stop()
+	}
+	d.ready.Broadcast()
+	d.mutex.Unlock()
+
+	d.workers.Wait()
+}
+
+// Enqueue queues the notification to be sent by the workers. It gets its ID
+// now, so that its deliveries can be looked up before they are sent.
+func (d *DispatchUseCase) Enqueue(notification entity.Notification) (entity.Job, error) {
+	if notification.ID == "" {
+		notification.ID = entity.NewID()
+	}
+
+	return d.enqueue(newJob(notification, ""))
+}
+
+// ReplayDeadLetter claims the dead letter and queues a job replaying it. It
+// returns notification.ErrReplayInProgress when it is being replayed already.
+func (d *DispatchUseCase) ReplayDeadLetter(id string) (entity.Job, error) {
+	deadLetter, err := d.NotificationUseCase.ClaimDeadLetter(id)
+	if err != nil {
+		return entity.Job{}, err
+	}
+
+	return d.enqueueReplay(deadLetter)
+}
+
+// ReplayDeadLetters queues a replay job for every dead letter not being
+// replayed already. It returns the jobs queued along with the first error.
+func (d *DispatchUseCase) ReplayDeadLetters() ([]entity.Job, error) {
+	jobs := []entity.Job{}
+	deadLetters, err := d.NotificationUseCase.ClaimDeadLetters()
+	for _, deadLetter := range deadLetters {
+		job, enqueueErr := d.enqueueReplay(deadLetter)
+		if enqueueErr != nil {
+			if err == nil {
+				err = enqueueErr
+			}
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, err
+}
+
+// enqueueReplay queues the replay of a claimed dead letter, and drops the
+// claim when it can't be queued.
+func (d *DispatchUseCase) enqueueReplay(deadLetter entity.DeadLetter) (entity.Job, error) {
+	job, err := d.enqueue(newJob(deadLetter.Notification, deadLetter.ID))
+	if err != nil {
+		if err := d.NotificationUseCase.ReleaseDeadLetter(deadLetter.ID); err != nil {
+			fmt.Printf("Failed to release dead letter %s: %v\n", deadLetter.ID, err)
+		}
+	}
+
+	return job, err
+}
+
+func newJob(notification entity.Notification, deadLetterID string) entity.Job {
+	now := time.Now()
+	return entity.Job{
+		ID:           entity.NewID(),
+		Notification: notification,
+		DeadLetterID: deadLetterID,
+		Status:       entity.JobQueued,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func (d *DispatchUseCase) enqueue(job entity.Job) (entity.Job, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.stopped {
+		return job, ErrStopped
+	}
+
+	if err := d.JobRepository.SaveJob(job); err != nil {
+		return job, err
+	}
+
+	d.push(job)
+
+	return job, nil
+}
+
+func (d *DispatchUseCase) GetJob(id string) (entity.Job, error) {
+	return d.JobRepository.GetJob(id)
+}
+
+func (d *DispatchUseCase) work() {
+	defer d.workers.Done()
+
+	for {
+		job, ok := d.next()
+		if !ok {
+			return
+		}
+
+		d.run(job)
+	}
+}
+
+func (d *DispatchUseCase) next() (entity.Job, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for len(d.queue) == 0 && !d.stopped {
+		d.ready.Wait()
+	}
+
+	if d.stopped {
+		return entity.Job{}, false
+	}
+
+	return heap.Pop(&d.queue).(queuedJob).job, true
+}
+
+// push queues the job, or holds it back when it is low priority and low
+// priority jobs are delayed. The mutex must be held.
+func (d *DispatchUseCase) push(job entity.Job) {
+	if job.Notification.EffectivePriority() == entity.PriorityLow && d.LowPriorityDelay > 0 {
+		d.held = append(d.held, job)
+		if d.delay == nil {
+			d.delay = time.AfterFunc(d.LowPriorityDelay, d.release)
+		}
+		return
+	}
+
+	heap.Push(&d.queue, queuedJob{job: job, sequence: d.sequence})
+	d.sequence++
+	d.ready.Signal()
+}
+
+// release queues the held back low priority jobs.
+func (d *DispatchUseCase) release() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.delay = nil
+	if d.stopped {
+		return
+	}
+
+	for _, job := range d.held {
+		heap.Push(&d.queue, queuedJob{job: job, sequence: d.sequence})
+		d.sequence++
+	}
+	d.held = nil
+	d.ready.Broadcast()
+}
+
+func (d *DispatchUseCase) run(job entity.Job) {
+	job.Status = entity.JobRunning
+	d.save(job)
+
+	result, err := d.send(&job)
+
+	if err != nil {
+		job.Status = entity.JobFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = entity.JobCompleted
+		job.Logs = result.Logs
+		job.Failed = result.Failed
+	}
+
+	d.save(job)
+	d.prune()
+}
+
+// send sends the job's notification, or replays its dead letter, and keeps
+// the job's progress up to date.
+func (d *DispatchUseCase) send(job *entity.Job) (entity.Result, error) {
+	if job.DeadLetterID != "" {
+		job.Total = 1
+		result, err := d.NotificationUseCase.ReplayDeadLetter(job.DeadLetterID)
+		if err == nil {
+			job.Processed = 1
+		}
+		return result, err
+	}
+
+	notification := job.Notification
+	notification.QueuedAt = job.CreatedAt
+	return d.NotificationUseCase.SendNotificationWithProgress(notification, func(processed int, total int) {
+		job.Processed = processed
+		job.Total = total
+		d.save(*job)
+	})
+}
+
+// prune removes the finished jobs older than the retention period.
+func (d *DispatchUseCase) prune() {
+	if d.JobRetention <= 0 {
+		return
+	}
+
+	if _, err := d.JobRepository.DeleteFinishedJobs(time.Now().Add(-d.JobRetention)); err != nil {
+		fmt.Printf("Failed to prune jobs: %v\n", err)
+	}
+}
+
+func (d *DispatchUseCase) save(job entity.Job) {
+	job.UpdatedAt = time.Now()
+	if err := d.JobRepository.SaveJob(job); err != nil {
+		fmt.Printf("Failed to save job %s: %v\n", job.ID, err)
+	}
+}
@@ -0,0 +1,215 @@
+package dispatch
+
+import (
+	"errors"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/notification"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var anyError = errors.New("Error")
+
+func TestDispatch_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewDispatchUseCase(notification.NewNotificationUseCase(logEntity, userEntity, deadLetterEntity), repositories.NewJobRepository(""), 2)
+	assert.NoError(t, service.Start())
+	defer service.Stop()
+
+	userEntity.EXPECT().GetUsersByCategory(entity.MoviesCategory).Return([]entity.User{getUser(3), getUser(4)}, nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil).Times(2)
+
+	job, err := service.Enqueue(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, entity.JobQueued, job.Status)
+
+	job = waitForJob(t, service, job.ID)
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, 2, job.Total)
+	assert.Equal(t, 2, job.Processed)
+	assert.Equal(t, 2, len(job.Logs))
+	assert.Equal(t, entity.LogQueued, job.Logs[0].History[0].Status)
+	assert.True(t, job.CreatedAt.Equal(job.Logs[0].History[0].Timestamp))
+	assert.Equal(t, entity.LogSent, job.Logs[0].History[1].Status)
+}
+
+func TestDispatch_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewDispatchUseCase(notification.NewNotificationUseCase(logEntity, userEntity, deadLetterEntity), repositories.NewJobRepository(""), 1)
+	assert.NoError(t, service.Start())
+	defer service.Stop()
+
+	userEntity.EXPECT().GetUsersByCategory(entity.MoviesCategory).Return(nil, anyError)
+
+	job, err := service.Enqueue(getNotification())
+	assert.NoError(t, err)
+
+	job = waitForJob(t, service, job.ID)
+	assert.Equal(t, entity.JobFailed, job.Status)
+	assert.Equal(t, "Error", job.Error)
+}
+
+func TestDispatch_Restart_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	jobRepository := repositories.NewJobRepository(t.TempDir() + "/jobs.json")
+
+	pending := entity.Job{ID: "pending", Notification: getNotification(), Status: entity.JobRunning}
+	assert.NoError(t, jobRepository.SaveJob(pending))
+
+	userEntity.EXPECT().GetUsersByCategory(entity.MoviesCategory).Return([]entity.User{getUser(3)}, nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil)
+
+	service := NewDispatchUseCase(notification.NewNotificationUseCase(logEntity, userEntity, deadLetterEntity), jobRepository, 1)
+	assert.NoError(t, service.Start())
+	defer service.Stop()
+
+	job := waitForJob(t, service, "pending")
+	assert.Equal(t, entity.JobCompleted, job.Status)
+}
+
+func TestDispatch_LowPriorityDelay_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewDispatchUseCase(notification.NewNotificationUseCase(logEntity, userEntity, deadLetterEntity), repositories.NewJobRepository(""), 1)
+	service.LowPriorityDelay = 100 * time.Millisecond
+	assert.NoError(t, service.Start())
+	defer service.Stop()
+
+	userEntity.EXPECT().GetUsersByCategory(entity.MoviesCategory).Return([]entity.User{getUser(3)}, nil).Times(3)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil).Times(3)
+
+	low := getNotification()
+	low.Priority = entity.PriorityLow
+	first, err := service.Enqueue(low)
+	assert.NoError(t, err)
+	second, err := service.Enqueue(low)
+	assert.NoError(t, err)
+	normal, err := service.Enqueue(getNotification())
+	assert.NoError(t, err)
+
+	job := waitForJob(t, service, normal.ID)
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	job, err = service.GetJob(first.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.JobQueued, job.Status)
+
+	first = waitForJob(t, service, first.ID)
+	second = waitForJob(t, service, second.ID)
+	assert.Equal(t, entity.JobCompleted, first.Status)
+	assert.Equal(t, entity.JobCompleted, second.Status)
+}
+
+func TestDispatch_Stopped_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	service := NewDispatchUseCase(notification.NewNotificationUseCase(log.NewMockLog(controller), log.NewMockUser(controller), log.NewMockDeadLetter(controller)), repositories.NewJobRepository(""), 1)
+	assert.NoError(t, service.Start())
+	service.Stop()
+
+	_, err := service.Enqueue(getNotification())
+	assert.ErrorIs(t, err, ErrStopped)
+}
+
+func TestDispatch_Replay_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterRepository := repositories.NewDeadLetterRepository(t.TempDir() + "/dead-letters.json")
+	jobRepository := repositories.NewJobRepository(t.TempDir() + "/jobs.json")
+	notificationUseCase := notification.NewNotificationUseCase(logEntity, userEntity, deadLetterRepository)
+	service := NewDispatchUseCase(notificationUseCase, jobRepository, 1)
+
+	assert.NoError(t, deadLetterRepository.SaveDeadLetter(entity.DeadLetter{
+		ID:           "dead",
+		Notification: getNotification(),
+		UserID:       3,
+		Channel:      entity.EmailChannel,
+		Attempts:     3,
+	}))
+
+	job, err := service.ReplayDeadLetter("dead")
+	assert.NoError(t, err)
+	assert.Equal(t, "dead", job.DeadLetterID)
+
+	_, err = service.ReplayDeadLetter("dead")
+	assert.ErrorIs(t, err, notification.ErrReplayInProgress)
+
+	jobs, err := service.ReplayDeadLetters()
+	assert.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	userEntity.EXPECT().GetUser(3).Return(getUser(3), nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil)
+
+	// The replay is queued in the job repository and sent after a restart.
+	service = NewDispatchUseCase(notificationUseCase, jobRepository, 1)
+	assert.NoError(t, service.Start())
+	defer service.Stop()
+
+	job = waitForJob(t, service, job.ID)
+	assert.Equal(t, entity.JobCompleted, job.Status)
+	assert.Equal(t, 1, job.Processed)
+	assert.Equal(t, 1, len(job.Logs))
+
+	_, err = deadLetterRepository.GetDeadLetter("dead")
+	assert.ErrorIs(t, err, repositories.ErrDeadLetterNotFound)
+}
+
+func waitForJob(t *testing.T, service *DispatchUseCase, id string) entity.Job {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := service.GetJob(id)
+		assert.NoError(t, err)
+		if job.IsFinished() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s did not finish", id)
+	return entity.Job{}
+}
+
+func getUser(id int) entity.User {
+	return entity.User{
+		ID:         id,
+		Name:       "Fred Williams",
+		Email:      "fred.williams@hotmail.com",
+		Subscribed: []entity.Category{entity.MoviesCategory},
+		Channels:   []entity.Channel{entity.EmailChannel},
+	}
+}
+
+func getNotification() entity.Notification {
+	return entity.Notification{
+		Message:  "test test",
+		Category: entity.MoviesCategory,
+	}
+}
@@ -0,0 +1,41 @@
+package dispatch
+
+import (
+	"notification/internal/entity"
+)
+
+type queuedJob struct {
+	job      entity.Job
+	sequence int
+}
+
+// jobQueue is a container/heap of queued jobs that serves higher priorities
+// first and, within a priority, the job queued first.
+type jobQueue []queuedJob
+
+func (q jobQueue) Len() int {
+	return len(q)
+}
+
+func (q jobQueue) Less(i, j int) bool {
+	left, right := q[i].job.Notification.EffectivePriority().Rank(), q[j].job.Notification.EffectivePriority().Rank()
+	if left != right {
+		return left > right
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *jobQueue) Push(x interface{}) {
+	*q = append(*q, x.(queuedJob))
+}
+
+func (q *jobQueue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,24 @@
+package dispatch
+
+import (
+	"container/heap"
+	"notification/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobQueue_Priority_Success(t *testing.T) {
+	queue := &jobQueue{}
+	priorities := []entity.Priority{entity.PriorityLow, "", entity.PriorityCritical, entity.PriorityHigh, entity.PriorityNormal, entity.PriorityCritical}
+	for i, priority := range priorities {
+		job := entity.Job{ID: string(rune('a' + i)), Notification: entity.Notification{Priority: priority}}
+		heap.Push(queue, queuedJob{job: job, sequence: i})
+	}
+
+	var ids string
+	for queue.Len() > 0 {
+		ids += heap.Pop(queue).(queuedJob).job.ID
+	}
+	assert.Equal(t, "cfdbea", ids)
+}
@@ -0,0 +1,106 @@
+package idempotency
+
+import (
+	"errors"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"sync"
+	"time"
+)
+
+var (
+	ErrKeyMismatch       = errors.New("idempotency key was already used with a different request")
+	ErrRequestInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// DefaultLease is how long a key stays reserved for a request that hasn't
+// finished, so that a key left behind by a crash can be used again.
+const DefaultLease = time.Minute
+
+// IdempotencyUseCase keeps the keys of each caller apart. A completed key is
+// kept for TTL after its request; a key still in progress is reserved for
+// Lease, after which Begin hands it to the next request.
+type IdempotencyUseCase struct {
+	IdempotencyRepository log.Idempotency
+	TTL                   time.Duration
+	Lease                 time.Duration
+
+	now   func() time.Time
+	mutex sync.Mutex
+}
+
+func NewIdempotencyUseCase(idempotency log.Idempotency, ttl time.Duration) *IdempotencyUseCase {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	return &IdempotencyUseCase{
+		IdempotencyRepository: idempotency,
+		TTL:                   ttl,
+		Lease:                 DefaultLease,
+		now:                   time.Now,
+	}
+}
+
+// Begin reserves the caller's key for a request with the fingerprint. It
+// returns the stored response when the same request was already handled, or
+// nil when the request should be handled now and then completed or
+// released. A reservation whose lease expired is taken over.
+func (u *IdempotencyUseCase) Begin(caller string, key string, fingerprint string) (*entity.StoredResponse, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	now := u.now()
+	if _, err := u.IdempotencyRepository.DeleteExpiredIdempotencyKeys(now); err != nil {
+		return nil, err
+	}
+
+	found, err := u.IdempotencyRepository.GetIdempotencyKey(caller, key)
+	switch {
+	case errors.Is(err, log.ErrIdempotencyKeyNotFound):
+		return nil, u.IdempotencyRepository.SaveIdempotencyKey(entity.IdempotencyKey{
+			Caller:      caller,
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(u.Lease),
+		})
+	case err != nil:
+		return nil, err
+	case found.Fingerprint != fingerprint:
+		return nil, ErrKeyMismatch
+	case found.Response == nil:
+		return nil, ErrRequestInProgress
+	default:
+		return found.Response, nil
+	}
+}
+
+// Complete stores the response returned to retries of the key's request
+// and keeps it until TTL after the request began.
+func (u *IdempotencyUseCase) Complete(caller string, key string, response entity.StoredResponse) error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	found, err := u.IdempotencyRepository.GetIdempotencyKey(caller, key)
+	if err != nil {
+		return err
+	}
+
+	found.Response = &response
+	found.ExpiresAt = found.CreatedAt.Add(u.TTL)
+	return u.IdempotencyRepository.SaveIdempotencyKey(found)
+}
+
+// Release forgets the key, so that the request can be retried, as after a
+// server error.
+func (u *IdempotencyUseCase) Release(caller string, key string) error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	err := u.IdempotencyRepository.DeleteIdempotencyKey(caller, key)
+	if errors.Is(err, log.ErrIdempotencyKeyNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package idempotency
+
+import (
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency_Success(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	service := NewIdempotencyUseCase(repositories.NewIdempotencyRepository(t.TempDir()+"/idempotency.json"), time.Hour)
+	service.now = func() time.Time { return now }
+
+	response, err := service.Begin("caller", "key", "add")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+
+	_, err = service.Begin("caller", "key", "add")
+	assert.ErrorIs(t, err, ErrRequestInProgress)
+
+	stored := entity.StoredResponse{StatusCode: 202, Body: `{"job_id":"1"}`}
+	assert.NoError(t, service.Complete("caller", "key", stored))
+
+	response, err = service.Begin("caller", "key", "add")
+	assert.NoError(t, err)
+	assert.Equal(t, &stored, response)
+
+	_, err = service.Begin("caller", "key", "delete")
+	assert.ErrorIs(t, err, ErrKeyMismatch)
+
+	service.now = func() time.Time { return now.Add(time.Hour) }
+	response, err = service.Begin("caller", "key", "delete")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
+
+func TestIdempotency_Release_Success(t *testing.T) {
+	service := NewIdempotencyUseCase(repositories.NewIdempotencyRepository(t.TempDir()+"/idempotency.json"), 0)
+	assert.Equal(t, 24*time.Hour, service.TTL)
+
+	_, err := service.Begin("caller", "key", "add")
+	assert.NoError(t, err)
+	assert.NoError(t, service.Release("caller", "key"))
+	assert.NoError(t, service.Release("caller", "key"))
+
+	response, err := service.Begin("caller", "key", "add")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
+
+func TestIdempotency_Lease_Success(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	service := NewIdempotencyUseCase(repositories.NewIdempotencyRepository(t.TempDir()+"/idempotency.json"), time.Hour)
+	service.now = func() time.Time { return now }
+
+	_, err := service.Begin("caller", "key", "add")
+	assert.NoError(t, err)
+
+	// A reservation left behind by a crash is taken over once its lease
+	// expired.
+	service.now = func() time.Time { return now.Add(DefaultLease) }
+	response, err := service.Begin("caller", "key", "add")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+
+	stored := entity.StoredResponse{StatusCode: 202}
+	assert.NoError(t, service.Complete("caller", "key", stored))
+
+	service.now = func() time.Time { return now.Add(2 * DefaultLease) }
+	response, err = service.Begin("caller", "key", "add")
+	assert.NoError(t, err)
+	assert.Equal(t, &stored, response)
+}
+
+func TestIdempotency_Caller_Success(t *testing.T) {
+	service := NewIdempotencyUseCase(repositories.NewIdempotencyRepository(t.TempDir()+"/idempotency.json"), time.Hour)
+
+	_, err := service.Begin("first", "key", "add")
+	assert.NoError(t, err)
+	assert.NoError(t, service.Complete("first", "key", entity.StoredResponse{StatusCode: 202}))
+
+	response, err := service.Begin("second", "key", "delete")
+	assert.NoError(t, err)
+	assert.Nil(t, response)
+}
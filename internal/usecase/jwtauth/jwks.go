@@ -0,0 +1,81 @@
+package jwtauth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// readJWKS loads the RSA and symmetric ("oct") keys of a JWKS file. Keys of
+// other types, or meant for encryption, are skipped.
+func readJWKS(path string) ([]verificationKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open JWKS file %s: %v", path, err)
+	}
+
+	var set jsonWebKeySet
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("Failed to parse JWKS file %s: %v", path, err)
+	}
+
+	var keys []verificationKey
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch {
+		case key.Kty == "RSA" && (key.Alg == "" || key.Alg == "RS256"):
+			publicKey, err := rsaPublicKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %q in %s: %v", key.Kid, path, err)
+			}
+			keys = append(keys, verificationKey{kid: key.Kid, alg: "RS256", key: publicKey})
+		case key.Kty == "oct" && (key.Alg == "" || key.Alg == "HS256"):
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("invalid key %q in %s", key.Kid, path)
+			}
+			keys = append(keys, verificationKey{kid: key.Kid, alg: "HS256", key: secret})
+		}
+	}
+
+	return keys, nil
+}
+
+func rsaPublicKey(key jsonWebKey) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil || len(modulus) == 0 {
+		return nil, fmt.Errorf("invalid modulus")
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil || len(exponent) == 0 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	e := new(big.Int).SetBytes(exponent)
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}, nil
+}
@@ -0,0 +1,148 @@
+package jwtauth
+
+import (
+	"errors"
+	"fmt"
+	"notification/internal/entity"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Config tells the verifier which tokens to accept. HS256 tokens are checked
+// against Secrets and the "oct" keys of the JWKS file, RS256 tokens against
+// its "RSA" keys. Audience is required; Tenant, when set, must match the
+// token's tenant claim.
+type Config struct {
+	Secrets     []string
+	JWKSPath    string
+	Audience    string
+	Tenant      string
+	RolesClaim  string
+	TenantClaim string
+	Leeway      time.Duration
+}
+
+type Verifier struct {
+	config Config
+	keys   []verificationKey
+	parser *jwt.Parser
+}
+
+// verificationKey is a key tokens can be signed with. Kid is empty for the
+// configured secrets, which are tried for any HS256 token.
+type verificationKey struct {
+	kid string
+	alg string
+	key interface{}
+}
+
+func NewVerifier(config Config) (*Verifier, error) {
+	if config.Audience == "" {
+		return nil, fmt.Errorf("an audience is required")
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	if config.TenantClaim == "" {
+		config.TenantClaim = "tenant"
+	}
+
+	var keys []verificationKey
+	for _, secret := range config.Secrets {
+		if secret != "" {
+			keys = append(keys, verificationKey{alg: "HS256", key: []byte(secret)})
+		}
+	}
+
+	if config.JWKSPath != "" {
+		jwks, err := readJWKS(config.JWKSPath)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwks...)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("a secret or a JWKS file is required")
+	}
+
+	return &Verifier{
+		config: config,
+		keys:   keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{"HS256", "RS256"}),
+			jwt.WithAudience(config.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(config.Leeway),
+		),
+	}, nil
+}
+
+// Verify checks the token's signature, exp, nbf and aud and returns its
+// subject, tenant and the scopes of its roles. Every failure wraps
+// ErrInvalidToken.
+func (v *Verifier) Verify(token string) (entity.Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFor); err != nil {
+		return entity.Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return entity.Principal{}, fmt.Errorf("%w: the sub claim is required", ErrInvalidToken)
+	}
+
+	tenant, _ := claims[v.config.TenantClaim].(string)
+	if v.config.Tenant != "" && tenant != v.config.Tenant {
+		return entity.Principal{}, fmt.Errorf("%w: the token is for another tenant", ErrInvalidToken)
+	}
+
+	principal := entity.Principal{Subject: subject, Tenant: tenant}
+	for _, role := range roles(claims[v.config.RolesClaim]) {
+		principal.Scopes = append(principal.Scopes, entity.Role(role).Scopes()...)
+	}
+
+	return principal, nil
+}
+
+// keyFor returns the keys that can have signed the token: those of its
+// algorithm and, when the token names one, its key ID.
+func (v *Verifier) keyFor(token *jwt.Token) (interface{}, error) {
+	alg, _ := token.Header["alg"].(string)
+	kid, _ := token.Header["kid"].(string)
+
+	var keys []jwt.VerificationKey
+	for _, key := range v.keys {
+		if key.alg == alg && (kid == "" || key.kid == "" || key.kid == kid) {
+			keys = append(keys, key.key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no %s key matches the token", alg)
+	}
+
+	return jwt.VerificationKeySet{Keys: keys}, nil
+}
+
+// roles reads the roles claim, either a list or a space-separated string.
+func roles(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		var names []string
+		for _, role := range value {
+			if name, ok := role.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,126 @@
+package jwtauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"notification/internal/entity"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const secret = "test-secret"
+
+func TestVerify_HS256_Success(t *testing.T) {
+	verifier, err := NewVerifier(Config{Secrets: []string{"old-secret", secret}, Audience: "notifications", Tenant: "acme"})
+	assert.NoError(t, err)
+
+	claims := getClaims()
+	claims["roles"] = []string{"sender", "log_admin", "unknown"}
+	principal, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, claims, []byte(secret)))
+	assert.NoError(t, err)
+	assert.Equal(t, "billing-service", principal.Subject)
+	assert.Equal(t, "acme", principal.Tenant)
+	assert.Equal(t, []entity.Scope{entity.ScopeSendNotifications, entity.ScopeReadLogs, entity.ScopeDeleteLogs}, principal.Scopes)
+}
+
+func TestVerify_RS256_Success(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	path := writeJWKS(t, "signing", &privateKey.PublicKey)
+
+	verifier, err := NewVerifier(Config{JWKSPath: path, Audience: "notifications", RolesClaim: "scope"})
+	assert.NoError(t, err)
+
+	claims := getClaims()
+	claims["scope"] = "log_reader"
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "signing"
+	signed, err := token.SignedString(privateKey)
+	assert.NoError(t, err)
+
+	principal, err := verifier.Verify(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Scope{entity.ScopeReadLogs}, principal.Scopes)
+
+	token.Header["kid"] = "rotated"
+	signed, err = token.SignedString(privateKey)
+	assert.NoError(t, err)
+	_, err = verifier.Verify(signed)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerify_Error(t *testing.T) {
+	verifier, err := NewVerifier(Config{Secrets: []string{secret}, Audience: "notifications", Tenant: "acme"})
+	assert.NoError(t, err)
+
+	invalid := map[string]func(claims jwt.MapClaims){
+		"expired":      func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"no expiry":    func(claims jwt.MapClaims) { delete(claims, "exp") },
+		"not yet":      func(claims jwt.MapClaims) { claims["nbf"] = time.Now().Add(time.Hour).Unix() },
+		"audience":     func(claims jwt.MapClaims) { claims["aud"] = "billing" },
+		"no subject":   func(claims jwt.MapClaims) { delete(claims, "sub") },
+		"other tenant": func(claims jwt.MapClaims) { claims["tenant"] = "globex" },
+	}
+	for name, change := range invalid {
+		claims := getClaims()
+		change(claims)
+		_, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, claims, []byte(secret)))
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodHS256, getClaims(), []byte("other-secret")))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodHS384, getClaims(), []byte(secret)))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewVerifier_Error(t *testing.T) {
+	_, err := NewVerifier(Config{Secrets: []string{secret}})
+	assert.Error(t, err)
+
+	_, err = NewVerifier(Config{Secrets: []string{""}, Audience: "notifications"})
+	assert.Error(t, err)
+
+	_, err = NewVerifier(Config{JWKSPath: t.TempDir() + "/missing.json", Audience: "notifications"})
+	assert.Error(t, err)
+}
+
+func getClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":    "billing-service",
+		"aud":    "notifications",
+		"tenant": "acme",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"nbf":    time.Now().Add(-time.Minute).Unix(),
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, claims jwt.MapClaims, key interface{}) string {
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func writeJWKS(t *testing.T, kid string, publicKey *rsa.PublicKey) string {
+	content, err := json.Marshal(jsonWebKeySet{Keys: []jsonWebKey{{
+		Kty: "RSA",
+		Kid: kid,
+		Alg: "RS256",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+	}}})
+	assert.NoError(t, err)
+
+	path := t.TempDir() + "/jwks.json"
+	assert.NoError(t, os.WriteFile(path, content, 0644))
+	return path
+}
@@ -0,0 +1,189 @@
+package notification
+
+import (
+	"errors"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"time"
+)
+
+var (
+	ErrReplayInProgress = errors.New("dead letter is already being replayed")
+	ErrChannelDisabled  = errors.New("user no longer receives notifications on this channel")
+)
+
+// replayLease is how long a claim for a replay is kept. A replay lost when
+// the process stopped can be started again once it has expired.
+const replayLease = time.Hour
+
+func (n NotificationUseCase) GetDeadLetters() ([]entity.DeadLetter, error) {
+	return n.DeadLetterRepository.GetDeadLetters()
+}
+
+func (n NotificationUseCase) GetDeadLetter(id string) (entity.DeadLetter, error) {
+	return n.DeadLetterRepository.GetDeadLetter(id)
+}
+
+// ClaimDeadLetter marks the dead letter as being replayed, so that it is
+// replayed only once until the replay is done. It returns
+// ErrReplayInProgress when another replay claimed it already.
+func (n NotificationUseCase) ClaimDeadLetter(id string) (entity.DeadLetter, error) {
+	n.deadLetters.Lock()
+	defer n.deadLetters.Unlock()
+
+	deadLetter, err := n.DeadLetterRepository.GetDeadLetter(id)
+	if err != nil {
+		return deadLetter, err
+	}
+
+	if deadLetter.IsReplaying(n.now(), replayLease) {
+		return deadLetter, ErrReplayInProgress
+	}
+
+	deadLetter.ReplayingSince = n.now()
+	return deadLetter, n.DeadLetterRepository.SaveDeadLetter(deadLetter)
+}
+
+// ClaimDeadLetters claims every dead letter not being replayed already.
+func (n NotificationUseCase) ClaimDeadLetters() ([]entity.DeadLetter, error) {
+	n.deadLetters.Lock()
+	defer n.deadLetters.Unlock()
+
+	deadLetters, err := n.DeadLetterRepository.GetDeadLetters()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := []entity.DeadLetter{}
+	for _, deadLetter := range deadLetters {
+		if deadLetter.IsReplaying(n.now(), replayLease) {
+			continue
+		}
+
+		deadLetter.ReplayingSince = n.now()
+		if err := n.DeadLetterRepository.SaveDeadLetter(deadLetter); err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, deadLetter)
+	}
+
+	return claimed, nil
+}
+
+// ReleaseDeadLetter drops the claim of a replay that won't run.
+func (n NotificationUseCase) ReleaseDeadLetter(id string) error {
+	n.deadLetters.Lock()
+	defer n.deadLetters.Unlock()
+
+	deadLetter, err := n.DeadLetterRepository.GetDeadLetter(id)
+	if err != nil {
+		return err
+	}
+
+	deadLetter.ReplayingSince = time.Time{}
+	return n.DeadLetterRepository.SaveDeadLetter(deadLetter)
+}
+
+// ReplayDeadLetter sends a claimed dead letter again through the normal send
+// path. A delivery sent or deferred to a schedule removes the entry; a failed
+// or dropped one updates its error and attempt count and drops the claim. It isn't sent when the user
+// was deleted or no longer receives notifications on its channel.
+func (n NotificationUseCase) ReplayDeadLetter(id string) (entity.Result, error) {
+	deadLetter, err := n.DeadLetterRepository.GetDeadLetter(id)
+	if err != nil {
+		return entity.Result{}, err
+	}
+
+	return n.replay(deadLetter)
+}
+
+func (n NotificationUseCase) replay(deadLetter entity.DeadLetter) (entity.Result, error) {
+	user, err := n.UserRepository.GetUser(deadLetter.UserID)
+	if errors.Is(err, log.ErrUserNotFound) {
+		return n.updateDeadLetter(deadLetter, entity.Delivery{
+			UserID:  deadLetter.UserID,
+			Channel: deadLetter.Channel,
+			Error:   err.Error(),
+		})
+	}
+	if err != nil {
+		return entity.Result{}, err
+	}
+
+	if !containsChannel(n.deliveryChannels(deadLetter.Notification, user), deadLetter.Channel) {
+		return n.updateDeadLetter(deadLetter, entity.Delivery{
+			UserID:  deadLetter.UserID,
+			Channel: deadLetter.Channel,
+			Error:   ErrChannelDisabled.Error(),
+		})
+	}
+
+	compiled, err := n.loadTemplate(deadLetter.Notification)
+	if err != nil {
+		return entity.Result{}, err
+	}
+
+	result, err := n.send(deadLetter.Notification, compiled, user, []entity.Channel{deadLetter.Channel})
+	if err != nil {
+		return result, err
+	}
+
+	if len(result.Failed) == 0 && delivered(result) {
+		return result, n.DeadLetterRepository.DeleteDeadLetter(deadLetter.ID)
+	}
+
+	delivery := entity.Delivery{UserID: deadLetter.UserID, Channel: deadLetter.Channel}
+	if len(result.Failed) > 0 {
+		delivery = result.Failed[0]
+	} else if len(result.Logs) > 0 {
+		delivery.Error = result.Logs[len(result.Logs)-1].Error
+	}
+
+	failed, err := n.updateDeadLetter(deadLetter, delivery)
+	failed.Logs = result.Logs
+	return failed, err
+}
+
+// delivered reports whether a message of the result was sent or deferred to
+// a schedule, rather than dropped by a rate limit.
+func delivered(result entity.Result) bool {
+	for _, log := range result.Logs {
+		if log.Status == entity.LogSent || log.Status == entity.LogDeferred {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n NotificationUseCase) updateDeadLetter(deadLetter entity.DeadLetter, delivery entity.Delivery) (entity.Result, error) {
+	deadLetter.Error = delivery.Error
+	deadLetter.Attempts += delivery.Attempts
+	deadLetter.ReplayingSince = time.Time{}
+	deadLetter.UpdatedAt = n.now()
+
+	delivery.Attempts = deadLetter.Attempts
+	delivery.DeadLetterID = deadLetter.ID
+
+	return entity.Result{Failed: []entity.Delivery{delivery}}, n.DeadLetterRepository.SaveDeadLetter(deadLetter)
+}
+
+func (n NotificationUseCase) saveDeadLetter(notification entity.Notification, delivery *entity.Delivery) error {
+	// A replay is queued again, so the time the notification was first
+	// queued doesn't apply to it.
+	notification.QueuedAt = time.Time{}
+	now := n.now()
+	deadLetter := entity.DeadLetter{
+		ID:           entity.NewID(),
+		Notification: notification,
+		UserID:       delivery.UserID,
+		Channel:      delivery.Channel,
+		Error:        delivery.Error,
+		Attempts:     delivery.Attempts,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	delivery.DeadLetterID = deadLetter.ID
+	return n.DeadLetterRepository.SaveDeadLetter(deadLetter)
+}
@@ -0,0 +1,190 @@
+package notification
+
+import (
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/notifiers"
+	"notification/internal/usecase/ratelimit"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayDeadLetter_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+
+	user := getUser(1)
+	user.Channels = []entity.Channel{entity.SMSChannel, entity.EmailChannel}
+
+	deadLetterEntity.EXPECT().GetDeadLetter("dead").Return(getDeadLetter("dead"), nil)
+	userEntity.EXPECT().GetUser(1).Return(user, nil)
+	logEntity.EXPECT().SaveLog(matchLog(getMessage(1, "SMS"))).Return(nil)
+	deadLetterEntity.EXPECT().DeleteDeadLetter("dead").Return(nil)
+
+	result, err := service.ReplayDeadLetter("dead")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Logs))
+	assert.Empty(t, result.Failed)
+}
+
+func TestReplayDeadLetter_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+	service.SMSUsecase = notifiers.NewSMSUsecase(&smsProvider{errors: []error{notifiers.ErrInvalidNumber}}, "1")
+	service.now = func() time.Time { return limitNow }
+
+	expected := getDeadLetter("dead")
+	expected.Attempts = 4
+	expected.Error = notifiers.ErrInvalidNumber.Error()
+
+	deadLetterEntity.EXPECT().GetDeadLetter("dead").Return(getDeadLetter("dead"), nil)
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	failed := getLimitMessage(1, "SMS", entity.LogFailed)
+	failed.Error = expected.Error
+	logEntity.EXPECT().SaveLog(matchLog(failed)).Return(nil)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(deadLetter entity.DeadLetter) error {
+		assert.Equal(t, expected.Attempts, deadLetter.Attempts)
+		assert.Equal(t, expected.Error, deadLetter.Error)
+		assert.Equal(t, limitNow, deadLetter.UpdatedAt)
+		return nil
+	})
+
+	result, err := service.ReplayDeadLetter("dead")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Logs))
+	assert.Equal(t, 1, len(result.Failed))
+	assert.Equal(t, "dead", result.Failed[0].DeadLetterID)
+
+	deadLetterEntity.EXPECT().GetDeadLetter("missing").Return(entity.DeadLetter{}, repositories.ErrDeadLetterNotFound)
+	_, err = service.ReplayDeadLetter("missing")
+	assert.ErrorIs(t, err, repositories.ErrDeadLetterNotFound)
+}
+
+func TestReplayDeadLetter_RateLimit_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+	limit := RateLimit{User: ratelimit.Limit{Count: 1, Per: time.Hour}, Policy: DropPolicy}
+	service.RateLimits = map[entity.Channel]RateLimit{entity.SMSChannel: limit}
+	service.now = func() time.Time { return limitNow }
+	service.RateLimiter.Take(limitNow, userRateLimit(getUser(1), entity.SMSChannel, limit))
+
+	dropped := getLimitMessage(1, "SMS", entity.LogDropped)
+	dropped.Error = ErrRateLimitExceeded.Error()
+	claimed := getDeadLetter("dead")
+	claimed.ReplayingSince = limitNow
+
+	deadLetterEntity.EXPECT().GetDeadLetter("dead").Return(claimed, nil)
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	logEntity.EXPECT().SaveLog(matchLog(dropped)).Return(nil)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(deadLetter entity.DeadLetter) error {
+		assert.Equal(t, ErrRateLimitExceeded.Error(), deadLetter.Error)
+		assert.True(t, deadLetter.ReplayingSince.IsZero())
+		return nil
+	})
+
+	result, err := service.ReplayDeadLetter("dead")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Logs))
+	assert.Equal(t, 1, len(result.Failed))
+	assert.Equal(t, "dead", result.Failed[0].DeadLetterID)
+}
+
+func TestReplayDeadLetter_Unreachable_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+
+	user := getUser(1)
+	user.Channels = []entity.Channel{entity.EmailChannel}
+	deleted := getDeadLetter("deleted-user")
+	deleted.UserID = 9
+
+	deadLetterEntity.EXPECT().GetDeadLetter("dead").Return(getDeadLetter("dead"), nil)
+	userEntity.EXPECT().GetUser(1).Return(user, nil)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(deadLetter entity.DeadLetter) error {
+		assert.Equal(t, 3, deadLetter.Attempts)
+		assert.Equal(t, ErrChannelDisabled.Error(), deadLetter.Error)
+		return nil
+	})
+
+	result, err := service.ReplayDeadLetter("dead")
+	assert.NoError(t, err)
+	assert.Empty(t, result.Logs)
+	assert.Equal(t, 1, len(result.Failed))
+
+	deadLetterEntity.EXPECT().GetDeadLetter("deleted-user").Return(deleted, nil)
+	userEntity.EXPECT().GetUser(9).Return(entity.User{}, repositories.ErrUserNotFound)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(deadLetter entity.DeadLetter) error {
+		assert.Equal(t, repositories.ErrUserNotFound.Error(), deadLetter.Error)
+		return nil
+	})
+
+	result, err = service.ReplayDeadLetter("deleted-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted-user", result.Failed[0].DeadLetterID)
+}
+
+func TestClaimDeadLetters_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+
+	replaying := getDeadLetter("replaying")
+	replaying.ReplayingSince = time.Now()
+	expired := getDeadLetter("expired")
+	expired.ReplayingSince = time.Now().Add(-2 * replayLease)
+
+	deadLetterEntity.EXPECT().GetDeadLetters().Return([]entity.DeadLetter{getDeadLetter("dead"), replaying, expired}, nil)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).Return(nil).Times(2)
+
+	claimed, err := service.ClaimDeadLetters()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(claimed))
+	assert.Equal(t, "dead", claimed[0].ID)
+	assert.Equal(t, "expired", claimed[1].ID)
+	assert.False(t, claimed[1].ReplayingSince.IsZero())
+
+	deadLetterEntity.EXPECT().GetDeadLetter("replaying").Return(replaying, nil)
+	_, err = service.ClaimDeadLetter("replaying")
+	assert.ErrorIs(t, err, ErrReplayInProgress)
+}
+
+func getDeadLetter(id string) entity.DeadLetter {
+	return entity.DeadLetter{
+		ID:           id,
+		Notification: getNotification(),
+		UserID:       1,
+		Channel:      entity.SMSChannel,
+		Error:        notifiers.ErrGatewayUnavailable.Error(),
+		Attempts:     3,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"notification/internal/entity"
+)
+
+// ErrDeliveryTimeout is returned for a delivery cancelled after
+// FallbackTimeout. A provider may still have accepted a request that was in
+// flight when it was cancelled.
+var ErrDeliveryTimeout = errors.New("delivery timed out and was cancelled")
+
+// chains groups the channels to send on. The user's fallback chain, or else
+// the category's, limited to the given channels, forms one group tried in
+// its own order; every other channel is sent on by itself.
+func (n NotificationUseCase) chains(notification entity.Notification, user entity.User, channels []entity.Channel) [][]entity.Channel {
+	fallback := user.Fallback
+	if len(fallback) == 0 {
+		fallback = n.CategoryFallbacks[notification.Category]
+	}
+
+	var chain []entity.Channel
+	for _, channel := range fallback {
+		if containsChannel(channels, channel) && !containsChannel(chain, channel) {
+			chain = append(chain, channel)
+		}
+	}
+	if len(chain) < 2 || notification.IsCritical() {
+		chain = nil
+	}
+
+	var groups [][]entity.Channel
+	chained := false
+	for _, channel := range channels {
+		switch {
+		case !containsChannel(chain, channel):
+			groups = append(groups, []entity.Channel{channel})
+		case !chained:
+			groups = append(groups, chain)
+			chained = true
+		}
+	}
+
+	return groups
+}
+
+// attempt delivers the content. When another channel can be tried after
+// this one, a delivery still running after FallbackTimeout, retries
+// included, is cancelled and fails with ErrDeliveryTimeout.
+func (n NotificationUseCase) attempt(notifier Notification, user entity.User, content entity.Content, fallback bool) (int, error) {
+	if !fallback || n.FallbackTimeout <= 0 {
+		return n.deliver(context.Background(), notifier, user, content)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.FallbackTimeout)
+	defer cancel()
+
+	attempts, err := n.deliver(ctx, notifier, user, content)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return attempts, ErrDeliveryTimeout
+	}
+
+	return attempts, err
+}
+
+func containsChannel(channels []entity.Channel, channel entity.Channel) bool {
+	for _, candidate := range channels {
+		if candidate == channel {
+			return true
+		}
+	}
+	return false
+}
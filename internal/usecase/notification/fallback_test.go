@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"context"
+	"notification/internal/entity"
+	"notification/internal/usecase/notifiers"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_Fallback_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service.SMSUsecase = notifiers.NewSMSUsecase(&smsProvider{errors: []error{notifiers.ErrInvalidNumber}}, "1")
+
+	user := getFallbackUser(1)
+	failed := getMessage(1, "SMS")
+	failed.Status = entity.LogFailed
+	failed.Error = "invalid phone number"
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{user}, nil)
+	gomock.InOrder(
+		logEntity.EXPECT().SaveLog(matchLog(failed)).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(getMessage(1, "E-Mail"))).Return(nil),
+	)
+
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Logs))
+	assert.Empty(t, result.Failed)
+}
+
+func TestNotification_Fallback_Category_Timeout_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	provider := &slowProvider{delay: time.Second}
+	service.SMSUsecase = notifiers.NewSMSUsecase(provider, "1")
+	service.CategoryFallbacks = map[entity.Category][]entity.Channel{
+		entity.SportsCategory: {entity.SMSChannel, entity.EmailChannel},
+	}
+	service.FallbackTimeout = 10 * time.Millisecond
+
+	user := getFallbackUser(1)
+	user.Fallback = nil
+	failed := getMessage(1, "SMS")
+	failed.Status = entity.LogFailed
+	failed.Error = ErrDeliveryTimeout.Error()
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{user}, nil)
+	gomock.InOrder(
+		logEntity.EXPECT().SaveLog(matchLog(failed)).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(getMessage(1, "E-Mail"))).Return(nil),
+	)
+
+	_, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.True(t, provider.cancelled)
+}
+
+func TestNotification_Fallback_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+	service.SMSUsecase = notifiers.NewSMSUsecase(&smsProvider{errors: []error{notifiers.ErrInvalidNumber}}, "1")
+	service.EmailUsecase = notifiers.NewEmailUsecase(notifiers.EmailConfig{Host: "localhost"})
+
+	user := getFallbackUser(1)
+	user.Email = ""
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{user}, nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil).Times(2)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).Return(nil)
+
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, entity.LogFailed, result.Logs[0].Status)
+	assert.Equal(t, entity.LogFailed, result.Logs[1].Status)
+	assert.Equal(t, 1, len(result.Failed))
+	assert.Equal(t, entity.EmailChannel, result.Failed[0].Channel)
+}
+
+func TestChains_Success(t *testing.T) {
+	service := NewNotificationUseCase(nil, nil, nil)
+	user := getFallbackUser(1)
+	user.Channels = []entity.Channel{entity.PushChannel, entity.EmailChannel, entity.SMSChannel}
+
+	assert.Equal(t, [][]entity.Channel{{entity.PushChannel}, {entity.SMSChannel, entity.EmailChannel}},
+		service.chains(getNotification(), user, user.Channels))
+	assert.Equal(t, [][]entity.Channel{{entity.EmailChannel}},
+		service.chains(getNotification(), user, []entity.Channel{entity.EmailChannel}))
+
+	critical := getNotification()
+	critical.Priority = entity.PriorityCritical
+	assert.Equal(t, 3, len(service.chains(critical, user, user.Channels)))
+}
+
+func getFallbackUser(id int) entity.User {
+	user := getUser(id)
+	user.Channels = []entity.Channel{entity.SMSChannel, entity.EmailChannel}
+	user.Fallback = []entity.Channel{entity.SMSChannel, entity.EmailChannel}
+	return user
+}
+
+type slowProvider struct {
+	delay     time.Duration
+	cancelled bool
+}
+
+func (p *slowProvider) SendSMS(ctx context.Context, to string, body string, reference string) error {
+	select {
+	case <-time.After(p.delay):
+		return nil
+	case <-ctx.Done():
+		p.cancelled = true
+		return ctx.Err()
+	}
+}
@@ -1,160 +1,392 @@
 package notification
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"notification/internal/entity"
 	log "notification/internal/platform/repositories"
 	"notification/internal/usecase/notifiers"
+	"notification/internal/usecase/ratelimit"
+	"notification/internal/usecase/template"
+	"sync"
 	"time"
 )
 
+var ErrNotificationNotFound = errors.New("notification not found")
+
 type NotificationUseCase struct {
-	LogRepository log.Log
-	SMSUsecase    *notifiers.SMSUsecase
-	EmailUsecase  *notifiers.EmailUsecase
-	PushUsecase   *notifiers.PushUsecase
+	LogRepository        log.Log
+	UserRepository       log.User
+	DeadLetterRepository log.DeadLetter
+	TemplateRepository   log.Template
+	ScheduleRepository   log.Schedule
+	SMSUsecase           *notifiers.SMSUsecase
+	EmailUsecase         *notifiers.EmailUsecase
+	PushUsecase          *notifiers.PushUsecase
+	RetryPolicy          RetryPolicy
+	CategoryFallbacks    map[entity.Category][]entity.Channel
+	FallbackTimeout      time.Duration
+	RateLimits           map[entity.Channel]RateLimit
+	RateLimiter          *ratelimit.Limiter
+
+	now func() time.Time
+	// receipts serializes RecordReceipt, which reads and then updates a log.
+	receipts *sync.Mutex
+	// deadLetters serializes claiming dead letters for a replay.
+	deadLetters *sync.Mutex
 }
 
+// Notification is a channel's notifier. SendContent gives up when ctx is
+// done, so that a delivery cut short by the fallback timeout doesn't arrive
+// after the next channel was tried.
 type Notification interface {
 	SendNotification(user entity.User, message string) error
+	SendContent(ctx context.Context, user entity.User, content entity.Content) error
 }
 
-func NewNotificationUseCase(log log.Log) *NotificationUseCase {
+func NewNotificationUseCase(log log.Log, user log.User, deadLetter log.DeadLetter) *NotificationUseCase {
 	smsUsecase := &notifiers.SMSUsecase{}
 	emailUsecase := &notifiers.EmailUsecase{}
 	pushUsecase := &notifiers.PushUsecase{}
 
 	return &NotificationUseCase{
-		LogRepository: log,
-		SMSUsecase:    smsUsecase,
-		EmailUsecase:  emailUsecase,
-		PushUsecase:   pushUsecase,
+		LogRepository:        log,
+		UserRepository:       user,
+		DeadLetterRepository: deadLetter,
+		SMSUsecase:           smsUsecase,
+		EmailUsecase:         emailUsecase,
+		PushUsecase:          pushUsecase,
+		RetryPolicy:          DefaultRetryPolicy(),
+		RateLimiter:          ratelimit.NewLimiter(),
+		now:                  time.Now,
+		receipts:             &sync.Mutex{},
+		deadLetters:          &sync.Mutex{},
 	}
 }
 
-func (n NotificationUseCase) SendNotification(notification entity.Notification) ([]entity.Log, error) {
-	var logs []entity.Log
-	users := n.GetUsersByCategory(notification.Category)
-	for _, user := range users {
-		logsOfUsers, err := n.send(notification, user)
+// Progress is called after every recipient with the number of recipients
+// already handled and the total number of recipients.
+type Progress func(processed int, total int)
+
+func (n NotificationUseCase) SendNotification(notification entity.Notification) (entity.Result, error) {
+	return n.SendNotificationWithProgress(notification, nil)
+}
+
+// SendNotificationWithProgress delivers the notification to every subscriber.
+// Failed deliveries are reported in the result and kept as dead letters
+// instead of stopping the remaining recipients; only infrastructure failures
+// return an error. A notification without an ID gets one.
+func (n NotificationUseCase) SendNotificationWithProgress(notification entity.Notification, progress Progress) (entity.Result, error) {
+	var result entity.Result
+	if notification.ID == "" {
+		notification.ID = entity.NewID()
+	}
+
+	users, err := n.GetUsersByCategory(notification.Category)
+	if err != nil {
+		return result, err
+	}
+
+	compiled, err := n.loadTemplate(notification)
+	if err != nil {
+		return result, err
+	}
+
+	for i, user := range users {
+		userResult, err := n.send(notification, compiled, user, n.deliveryChannels(notification, user))
 		if err != nil {
-			return nil, err
+			return result, err
 		}
 
-		for _, log := range logsOfUsers {
-			logs = append(logs, log)
+		for i := range userResult.Failed {
+			if err := n.saveDeadLetter(notification, &userResult.Failed[i]); err != nil {
+				return result, err
+			}
+		}
+
+		result.Logs = append(result.Logs, userResult.Logs...)
+		result.Failed = append(result.Failed, userResult.Failed...)
+
+		if progress != nil {
+			progress(i+1, len(users))
 		}
 	}
 
-	return logs, nil
+	return result, nil
 }
 
-func (n NotificationUseCase) GetUsersByCategory(category entity.Category) []entity.User {
-	var users []entity.User
+func (n NotificationUseCase) GetUsersByCategory(category entity.Category) ([]entity.User, error) {
+	return n.UserRepository.GetUsersByCategory(category)
+}
 
-	switch category {
-	case entity.SportsCategory:
-		users = []entity.User{
-			{
-				ID:          1,
-				Name:        "Mary Alexander",
-				Email:       "mary.alexander@outlook.com",
-				PhoneNumber: "78958745",
-				Subscribed:  []entity.Category{entity.SportsCategory},
-				Channels:    []entity.Channel{"SMS"},
-			},
-		}
-	case entity.FinanceCategory:
-		users = []entity.User{
-			{
-				ID:          2,
-				Name:        "Antony Smith",
-				Email:       "antony.smith@gmail.com",
-				PhoneNumber: "4134132441",
-				Subscribed:  []entity.Category{entity.FinanceCategory},
-				Channels:    []entity.Channel{"Email", "Push"},
-			},
-		}
-	case entity.MoviesCategory:
-		users = []entity.User{
-			{
-				ID:          3,
-				Name:        "Any Johnson",
-				Email:       "any.johnson@gmail.com",
-				PhoneNumber: "+123456789",
-				Subscribed:  []entity.Category{entity.MoviesCategory},
-				Channels:    []entity.Channel{"SMS", "Email"},
-			},
-			{
-				ID:          4,
-				Name:        "Fred Williams",
-				Email:       "fred.williams@hotmail.com",
-				PhoneNumber: "78459214465",
-				Subscribed:  []entity.Category{entity.MoviesCategory},
-				Channels:    []entity.Channel{"Email"},
-			},
+// GetLogs returns one page of the logs matching the filter, together with
+// the number of matching logs across every page.
+func (n NotificationUseCase) GetLogs(filter entity.LogFilter) ([]entity.Log, int, error) {
+	return n.LogRepository.GetLogsPage(filter)
+}
+
+// GetNotification returns the logs of every delivery of the notification,
+// oldest first, and its failed deliveries still kept as dead letters.
+func (n NotificationUseCase) GetNotification(id string) (entity.NotificationDeliveries, error) {
+	deliveries := entity.NotificationDeliveries{ID: id, DeadLetters: []entity.DeadLetter{}}
+
+	logs, err := n.LogRepository.GetLogs(entity.LogFilter{NotificationID: id, Sort: entity.SortAscending})
+	if err != nil {
+		return deliveries, err
+	}
+	deliveries.Logs = logs
+
+	deadLetters, err := n.DeadLetterRepository.GetDeadLetters()
+	if err != nil {
+		return deliveries, err
+	}
+	for _, deadLetter := range deadLetters {
+		if deadLetter.Notification.ID == id {
+			deliveries.DeadLetters = append(deliveries.DeadLetters, deadLetter)
 		}
 	}
 
-	return users
+	if len(deliveries.Logs) == 0 && len(deliveries.DeadLetters) == 0 {
+		return deliveries, ErrNotificationNotFound
+	}
+
+	return deliveries, nil
 }
 
-func (n NotificationUseCase) GetLogs() ([]entity.Log, error) {
-	return n.LogRepository.GetLogs()
+func (n NotificationUseCase) DeleteLogs(filter entity.LogFilter) (int, error) {
+	return n.LogRepository.DeleteLogs(filter)
 }
 
-func (n NotificationUseCase) DeleteLogs() error {
-	return n.LogRepository.DeleteLogs()
+// deliveryChannels returns the channels the notification is sent on to the
+// user: the enabled ones, or every reachable one for critical notifications.
+func (n NotificationUseCase) deliveryChannels(notification entity.Notification, user entity.User) []entity.Channel {
+	if notification.IsCritical() {
+		return user.ReachableChannels(n.hasDevices(user))
+	}
+
+	return user.Channels
 }
 
-func (n NotificationUseCase) send(notification entity.Notification, user entity.User) ([]entity.Log, error) {
-	var logs []entity.Log
-	notifiers := n.getNotifiers(user.Channels)
-	for _, notifier := range notifiers {
-		err := notifier.SendNotification(user, notification.Message)
-		if err != nil {
-			return nil, err
+// hasDevices reports whether the user registered a device for push. When the
+// devices can't be read, push is left out and the other channels still go.
+func (n NotificationUseCase) hasDevices(user entity.User) bool {
+	if n.PushUsecase == nil || n.PushUsecase.DeviceRepository == nil {
+		return false
+	}
+
+	devices, err := n.PushUsecase.DeviceRepository.GetDevicesByUser(user.ID)
+	return err == nil && len(devices) > 0
+}
+
+// send delivers the notification, rendered with its compiled template, to
+// the user on each channel. Channels in the user's fallback chain are tried
+// one after another instead, until one of them succeeds.
+func (n NotificationUseCase) send(notification entity.Notification, compiled *template.Compiled, user entity.User, channels []entity.Channel) (entity.Result, error) {
+	var result entity.Result
+	// Dead letters and schedules stored before notifications had IDs are
+	// given one when they are replayed.
+	if notification.ID == "" {
+		notification.ID = entity.NewID()
+	}
+	for _, chain := range n.chains(notification, user, channels) {
+		if err := n.sendChain(notification, compiled, user, chain, &result); err != nil {
+			return result, err
 		}
+	}
 
-		notificationType := n.getNotificationType(notifier)
+	return result, nil
+}
 
-		log := entity.Log{
-			ID:               fmt.Sprintf("%v-%s-%s", user.ID, notification.Category, notificationType),
-			UserID:           user.ID,
-			Message:          notification.Message,
-			Category:         notification.Category,
-			NotificationType: notificationType,
-			Timestamp:        time.Now(),
+// sendChain tries the channels in order until a message is sent, deferred
+// or downgraded to another channel. Every failed delivery is logged; when the
+// last channel fails too, it is reported as a failed delivery.
+func (n NotificationUseCase) sendChain(notification entity.Notification, compiled *template.Compiled, user entity.User, chain []entity.Channel, result *entity.Result) error {
+	for i := range chain {
+		done, err := n.sendOn(notification, compiled, user, chain, i, result)
+		if err != nil || done {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// sendOn delivers on the chain's channel at index and reports whether the
+// chain is done.
+func (n NotificationUseCase) sendOn(notification entity.Notification, compiled *template.Compiled, user entity.User, chain []entity.Channel, index int, result *entity.Result) (bool, error) {
+	channel := chain[index]
+	last := index == len(chain)-1
+
+	notifier := n.getNotifier(channel)
+	if notifier == nil {
+		return false, nil
+	}
 
-		err = n.LogRepository.SaveLog(log)
+	notificationType := n.getNotificationType(notifier)
+	content, err := n.render(notification, compiled, user, channel)
+	log := entity.Log{
+		ID:               entity.NewID(),
+		NotificationID:   notification.ID,
+		UserID:           user.ID,
+		Message:          content.Body,
+		Category:         notification.Category,
+		NotificationType: notificationType,
+		Locale:           content.Locale,
+		Status:           entity.LogSent,
+		Timestamp:        n.now(),
+		RequestedBy:      notification.RequestedBy,
+	}
+
+	attempts := 0
+	if err == nil {
+		if until, quiet := n.quietUntil(notification, user, channel); quiet {
+			if err := n.deferDelivery(notification, user, channel, until); err != nil {
+				return true, err
+			}
+			log.Status = entity.LogDeferred
+			return true, n.saveLog(notification, log, result)
+		}
+
+		if limit, wait, limited := n.rateLimited(notification, user, channel, content); limited {
+			return n.overLimit(notification, compiled, user, channel, limit, wait, log, result)
+		}
+
+		content.Reference = log.ID
+		attempts, err = n.attempt(notifier, user, content, !last)
 		if err != nil {
-			return nil, err
+			n.refundRateLimit(notification, user, channel)
 		}
+	}
+
+	if err == nil {
+		return true, n.saveLog(notification, log, result)
+	}
+
+	log.Status = entity.LogFailed
+	log.Error = err.Error()
+	if err := n.saveLog(notification, log, result); err != nil {
+		return true, err
+	}
+
+	if last {
+		result.Failed = append(result.Failed, entity.Delivery{
+			UserID:           user.ID,
+			Channel:          channel,
+			NotificationType: notificationType,
+			Attempts:         attempts,
+			Error:            err.Error(),
+		})
+	}
+
+	return false, nil
+}
+
+// saveLog saves the log of one of the notification's deliveries and adds it
+// to the result.
+func (n NotificationUseCase) saveLog(notification entity.Notification, log entity.Log, result *entity.Result) error {
+	if !notification.QueuedAt.IsZero() {
+		log = log.Queued(notification.QueuedAt)
+	}
+
+	if err := n.LogRepository.SaveLog(log); err != nil {
+		return err
+	}
 
-		logs = append(logs, log)
+	result.Logs = append(result.Logs, log)
+	return nil
+}
+
+// CheckNotification reports whether the template a notification refers to
+// exists and parses, so a missing one is rejected before the notification is
+// queued.
+func (n NotificationUseCase) CheckNotification(notification entity.Notification) error {
+	_, err := n.compile(notification)
+	return err
+}
+
+// compile loads and parses the notification's template once for all its
+// recipients. A notification without a template has none.
+func (n NotificationUseCase) compile(notification entity.Notification) (*template.Compiled, error) {
+	if notification.TemplateID == "" {
+		return nil, nil
+	}
+
+	if n.TemplateRepository == nil {
+		return nil, log.ErrTemplateNotFound
 	}
 
-	return logs, nil
+	found, err := n.TemplateRepository.GetTemplate(notification.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.Compile(found)
 }
 
-func (n NotificationUseCase) getNotifiers(channels []entity.Channel) []Notification {
-	notifiers := make([]Notification, 0)
-	fmt.Println(len(notifiers))
+// loadTemplate compiles the notification's template for sending. A template
+// removed since the notification was accepted fails each delivery, as render
+// reports it, instead of the whole notification.
+func (n NotificationUseCase) loadTemplate(notification entity.Notification) (*template.Compiled, error) {
+	compiled, err := n.compile(notification)
+	if errors.Is(err, log.ErrTemplateNotFound) {
+		return nil, nil
+	}
+
+	return compiled, err
+}
 
-	for _, channel := range channels {
-		switch channel {
-		case entity.EmailChannel:
-			notifiers = append(notifiers, n.EmailUsecase)
-		case entity.SMSChannel:
-			notifiers = append(notifiers, n.SMSUsecase)
-		case entity.PushChannel:
-			notifiers = append(notifiers, n.PushUsecase)
+// render returns the raw message, or the notification's compiled template
+// rendered for the user and channel, in the user's locale when there is a
+// variant for it.
+func (n NotificationUseCase) render(notification entity.Notification, compiled *template.Compiled, user entity.User, channel entity.Channel) (entity.Content, error) {
+	if notification.TemplateID == "" {
+		message, locale := notification.Localize(user.Locale)
+		return entity.Content{Body: message, Locale: locale}, nil
+	}
+
+	if compiled == nil {
+		return entity.Content{}, log.ErrTemplateNotFound
+	}
+
+	return compiled.Render(channel, template.Data{
+		User:     user,
+		Category: notification.Category,
+		Message:  notification.Message,
+		Data:     notification.Data,
+	})
+}
+
+// deliver sends the content through the notifier, retrying transient
+// failures according to the retry policy until ctx is done. It returns the
+// number of attempts.
+func (n NotificationUseCase) deliver(ctx context.Context, notifier Notification, user entity.User, content entity.Content) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := notifier.SendContent(ctx, user, content)
+		if err == nil || !notifiers.IsRetryable(err) || attempt >= n.RetryPolicy.MaxAttempts {
+			return attempt, err
 		}
 
+		wait := time.NewTimer(n.RetryPolicy.Delay(attempt))
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			wait.Stop()
+			return attempt, err
+		}
 	}
+}
 
-	return notifiers
+func (n NotificationUseCase) getNotifier(channel entity.Channel) Notification {
+	switch channel {
+	case entity.EmailChannel:
+		return n.EmailUsecase
+	case entity.SMSChannel:
+		return n.SMSUsecase
+	case entity.PushChannel:
+		return n.PushUsecase
+	default:
+		return nil
+	}
 }
 
 func (n NotificationUseCase) getNotificationType(notifier Notification) string {
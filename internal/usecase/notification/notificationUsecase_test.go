@@ -1,15 +1,20 @@
 package notification
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/notifiers"
 	log "notification/test/platform"
 	//notification "notification/test/usecase"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,28 +25,319 @@ func TestNotification_Success(t *testing.T) {
 
 	defer controller.Finish()
 	logEntity := log.NewMockLog(controller)
-	service := NewNotificationUseCase(logEntity)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
 
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
 	message := getMessage(1, "SMS")
-	logEntity.EXPECT().SaveLog(message).Return(nil)
+	logEntity.EXPECT().SaveLog(matchLog(message)).Return(nil)
 
 	_, err := service.SendNotification(getNotification())
 	assert.NoError(t, err)
 
 }
 
+func TestNotification_Critical_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+
+	deviceEntity := log.NewMockDevice(controller)
+	service.PushUsecase = notifiers.NewPushUsecase(notifiers.PushConfig{}, deviceEntity)
+
+	notification := getNotification()
+	notification.Priority = entity.PriorityCritical
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil).Times(2)
+	gomock.InOrder(
+		deviceEntity.EXPECT().GetDevicesByUser(1).Return(nil, nil),
+		deviceEntity.EXPECT().GetDevicesByUser(1).Return([]entity.Device{{Token: "token", UserID: 1}}, nil),
+	)
+	logEntity.EXPECT().SaveLog(matchLog(getMessage(1, "SMS"))).Return(nil).Times(2)
+	logEntity.EXPECT().SaveLog(matchLog(getMessage(1, "E-Mail"))).Return(nil).Times(2)
+	logEntity.EXPECT().SaveLog(matchLog(getMessage(1, "Push Notification"))).Return(nil)
+
+	// Critical notifications go out on every channel the user can be
+	// reached on, including push once a device is registered.
+	result, err := service.SendNotification(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Logs))
+
+	result, err = service.SendNotification(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(result.Logs))
+}
+
+func TestNotification_Urgent_Success(t *testing.T) {
+	notification := getNotification()
+	notification.Urgent = true
+
+	assert.True(t, notification.IsCritical())
+	assert.Equal(t, entity.PriorityCritical, notification.EffectivePriority())
+}
+
+func TestNotification_GetUsers_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return(nil, anyError)
+
+	_, err := service.SendNotification(getNotification())
+	assert.Error(t, err)
+
+}
+
+func TestNotification_Retry_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+	service.RetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	provider := &smsProvider{errors: []error{notifiers.ErrGatewayUnavailable}}
+	service.SMSUsecase = notifiers.NewSMSUsecase(provider, "1")
+
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+	logEntity.EXPECT().SaveLog(matchLog(getMessage(1, "SMS"))).Return(nil)
+
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+	assert.Equal(t, 1, len(result.Logs))
+	assert.Empty(t, result.Failed)
+}
+
+func TestNotification_Retry_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+	service.RetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	provider := &smsProvider{errors: []error{
+		notifiers.ErrRateLimited,
+		notifiers.ErrRateLimited,
+		notifiers.ErrRateLimited,
+		notifiers.ErrInvalidNumber,
+	}}
+	service.SMSUsecase = notifiers.NewSMSUsecase(provider, "1")
+
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1), getUser(2), getUser(3)}, nil)
+	logEntity.EXPECT().SaveLog(matchLog(getFailedMessage(1, "SMS", notifiers.ErrRateLimited.Error()))).Return(nil)
+	logEntity.EXPECT().SaveLog(matchLog(getFailedMessage(2, "SMS", notifiers.ErrInvalidNumber.Error()))).Return(nil)
+	logEntity.EXPECT().SaveLog(matchLog(getMessage(3, "SMS"))).Return(nil)
+
+	var deadLetters []entity.DeadLetter
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).DoAndReturn(func(deadLetter entity.DeadLetter) error {
+		deadLetters = append(deadLetters, deadLetter)
+		return nil
+	}).Times(2)
+
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, provider.calls)
+	assert.Equal(t, 3, len(result.Logs))
+	assert.Equal(t, entity.LogFailed, result.Logs[0].Status)
+	assert.Equal(t, 3, result.Logs[2].UserID)
+
+	assert.Equal(t, 2, len(result.Failed))
+	assert.Equal(t, entity.Delivery{UserID: 1, Channel: entity.SMSChannel, NotificationType: "SMS", Attempts: 3, Error: notifiers.ErrRateLimited.Error(), DeadLetterID: deadLetters[0].ID}, result.Failed[0])
+	assert.Equal(t, entity.Delivery{UserID: 2, Channel: entity.SMSChannel, NotificationType: "SMS", Attempts: 1, Error: notifiers.ErrInvalidNumber.Error(), DeadLetterID: deadLetters[1].ID}, result.Failed[1])
+
+	expected := getNotification()
+	expected.ID = result.Logs[0].NotificationID
+	assert.Equal(t, expected, deadLetters[0].Notification)
+	assert.Equal(t, 1, deadLetters[0].UserID)
+	assert.Equal(t, entity.SMSChannel, deadLetters[0].Channel)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+}
+
+func TestNotification_Template_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	templateEntity := log.NewMockTemplate(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service.TemplateRepository = templateEntity
+
+	provider := &smsProvider{}
+	service.SMSUsecase = notifiers.NewSMSUsecase(provider, "1")
+
+	notification := getNotification()
+	notification.TemplateID = "goal"
+	notification.Data = map[string]interface{}{"team": "Lions"}
+
+	template := entity.Template{ID: "goal", Body: "Hi {{.User.Name}}, {{.Data.team}} scored"}
+	templateEntity.EXPECT().GetTemplate("goal").Return(template, nil)
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+
+	message := getMessage(1, "SMS")
+	message.Message = "Hi Mary Alexander, Lions scored"
+	logEntity.EXPECT().SaveLog(matchLog(message)).Return(nil)
+
+	result, err := service.SendNotification(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Logs))
+	assert.Equal(t, []string{"Hi Mary Alexander, Lions scored"}, provider.bodies)
+}
+
+func TestNotification_Template_Loaded_Once_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	templateEntity := log.NewMockTemplate(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service.TemplateRepository = templateEntity
+
+	notification := getNotification()
+	notification.TemplateID = "goal"
+
+	users := []entity.User{getUser(1), getUser(2)}
+	for i := range users {
+		users[i].Channels = []entity.Channel{entity.SMSChannel, entity.EmailChannel}
+	}
+
+	templateEntity.EXPECT().GetTemplate("goal").Return(entity.Template{ID: "goal", Body: "Hi {{.User.Name}}"}, nil).Times(1)
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return(users, nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil).Times(4)
+
+	result, err := service.SendNotification(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(result.Logs))
+}
+
+func TestNotification_Locale_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+
+	provider := &smsProvider{}
+	service.SMSUsecase = notifiers.NewSMSUsecase(provider, "1")
+
+	notification := getNotification()
+	notification.Messages = map[string]string{"pt": "teste teste", "es": "prueba"}
+
+	brazilian := getUser(1)
+	brazilian.Locale = "pt-BR"
+	french := getUser(2)
+	french.Locale = "fr"
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{brazilian, french}, nil)
+
+	translated := getMessage(1, "SMS")
+	translated.Message = "teste teste"
+	translated.Locale = "pt"
+	logEntity.EXPECT().SaveLog(matchLog(translated)).Return(nil)
+	logEntity.EXPECT().SaveLog(matchLog(getMessage(2, "SMS"))).Return(nil)
+
+	_, err := service.SendNotification(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"teste teste", "test test"}, provider.bodies)
+}
+
+func TestNotification_Template_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	templateEntity := log.NewMockTemplate(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, deadLetterEntity)
+	service.TemplateRepository = templateEntity
+
+	notification := getNotification()
+	notification.TemplateID = "goal"
+
+	templateEntity.EXPECT().GetTemplate("goal").Return(entity.Template{ID: "goal", Body: "{{.Data.team}} scored"}, nil)
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).Return(nil)
+
+	result, err := service.SendNotification(notification)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Failed))
+	assert.Equal(t, 0, result.Failed[0].Attempts)
+	assert.Equal(t, entity.LogFailed, result.Logs[0].Status)
+
+	templateEntity.EXPECT().GetTemplate("missing").Return(entity.Template{}, repositories.ErrTemplateNotFound)
+	notification.TemplateID = "missing"
+	assert.ErrorIs(t, service.CheckNotification(notification), repositories.ErrTemplateNotFound)
+}
+
 func TestSendNotification_GetLogs_Success(t *testing.T) {
 	controller := gomock.NewController(t)
 
 	defer controller.Finish()
 	logEntity := log.NewMockLog(controller)
-	service := NewNotificationUseCase(logEntity)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), log.NewMockDeadLetter(controller))
+
+	filter := entity.LogFilter{UserID: 1, Limit: 10}
+	logEntity.EXPECT().GetLogsPage(filter).Return([]entity.Log{getMessage(1, "SMS")}, 25, nil)
+
+	logs, total, err := service.GetLogs(filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(logs))
+	assert.Equal(t, 25, total)
+
+}
+
+func TestGetNotification_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), deadLetterEntity)
 
-	logEntity.EXPECT().GetLogs().Return([]entity.Log{}, nil)
+	message := getMessage(1, "SMS")
+	message.NotificationID = "batch"
+	failed := entity.DeadLetter{ID: "dead", Notification: getNotification()}
+	failed.Notification.ID = "batch"
+	logEntity.EXPECT().GetLogs(entity.LogFilter{NotificationID: "batch", Sort: entity.SortAscending}).Return([]entity.Log{message}, nil)
+	deadLetterEntity.EXPECT().GetDeadLetters().Return([]entity.DeadLetter{failed, {ID: "other", Notification: getNotification()}}, nil)
 
-	_, err := service.GetLogs()
+	deliveries, err := service.GetNotification("batch")
 	assert.NoError(t, err)
+	assert.Equal(t, "batch", deliveries.ID)
+	assert.Equal(t, []entity.Log{message}, deliveries.Logs)
+	assert.Equal(t, []entity.DeadLetter{failed}, deliveries.DeadLetters)
+}
 
+func TestGetNotification_NotFound_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), deadLetterEntity)
+
+	logEntity.EXPECT().GetLogs(gomock.Any()).Return([]entity.Log{}, nil)
+	deadLetterEntity.EXPECT().GetDeadLetters().Return([]entity.DeadLetter{}, nil)
+
+	_, err := service.GetNotification("missing")
+	assert.ErrorIs(t, err, ErrNotificationNotFound)
 }
 
 func TestSendNotification_DeleteLogs_Success(t *testing.T) {
@@ -49,12 +345,14 @@ func TestSendNotification_DeleteLogs_Success(t *testing.T) {
 
 	defer controller.Finish()
 	logEntity := log.NewMockLog(controller)
-	service := NewNotificationUseCase(logEntity)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), log.NewMockDeadLetter(controller))
 
-	logEntity.EXPECT().DeleteLogs().Return(nil)
+	filter := entity.LogFilter{Category: entity.SportsCategory}
+	logEntity.EXPECT().DeleteLogs(filter).Return(3, nil)
 
-	err := service.DeleteLogs()
+	deleted, err := service.DeleteLogs(filter)
 	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
 
 }
 
@@ -71,18 +369,80 @@ func getUser(id int) entity.User {
 
 func getMessage(id int, NotificationType string) entity.Log {
 	return entity.Log{
-		ID:               fmt.Sprintf("%v-%s-%s", id, string(entity.SportsCategory), string(NotificationType)),
 		UserID:           id,
 		Message:          "test test",
 		Category:         entity.SportsCategory,
 		NotificationType: NotificationType,
+		Locale:           entity.DefaultLocale,
+		Status:           entity.LogSent,
 		Timestamp:        time.Now(),
 	}
 }
 
+func getFailedMessage(id int, NotificationType string, err string) entity.Log {
+	message := getMessage(id, NotificationType)
+	message.Status = entity.LogFailed
+	message.Error = err
+	return message
+}
+
 func getNotification() entity.Notification {
 	return entity.Notification{
 		Message:  "test test",
 		Category: entity.SportsCategory,
 	}
 }
+
+type smsProvider struct {
+	errors []error
+	calls  int
+	bodies []string
+}
+
+func (p *smsProvider) SendSMS(ctx context.Context, to string, body string, reference string) error {
+	p.calls++
+	p.bodies = append(p.bodies, body)
+	if p.calls <= len(p.errors) {
+		return p.errors[p.calls-1]
+	}
+	return nil
+}
+
+type logMatcher struct {
+	log entity.Log
+}
+
+func matchLog(log entity.Log) gomock.Matcher {
+	return logMatcher{log: log}
+}
+
+func (m logMatcher) Matches(x interface{}) bool {
+	log, ok := x.(entity.Log)
+	if !ok || log.Timestamp.Sub(m.log.Timestamp).Abs() > time.Second {
+		return false
+	}
+
+	// IDs are generated, so only their form is checked unless the expected
+	// log sets them.
+	if !isGeneratedID(log.ID) || (m.log.NotificationID == "" && !isGeneratedID(log.NotificationID)) {
+		return false
+	}
+	if m.log.ID == "" {
+		log.ID = ""
+	}
+	if m.log.NotificationID == "" {
+		log.NotificationID = ""
+	}
+
+	log.Timestamp = m.log.Timestamp
+	return reflect.DeepEqual(log, m.log)
+}
+
+func isGeneratedID(id string) bool {
+	parsed, err := uuid.Parse(id)
+	return err == nil && parsed.Version() == 7
+}
+
+func (m logMatcher) String() string {
+	return fmt.Sprintf("matches %v", m.log)
+}
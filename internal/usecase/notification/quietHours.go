@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"errors"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"time"
+)
+
+// SendToUser sends the notification to one user on one channel, as for a
+// delivery deferred by quiet hours. Nothing is sent when the user has since
+// been removed, unsubscribed from the category or disabled the channel.
+func (n NotificationUseCase) SendToUser(notification entity.Notification, userID int, channel entity.Channel) (entity.Result, error) {
+	user, err := n.UserRepository.GetUser(userID)
+	if errors.Is(err, log.ErrUserNotFound) {
+		return entity.Result{}, nil
+	}
+	if err != nil {
+		return entity.Result{}, err
+	}
+
+	if !user.IsSubscribed(notification.Category) || !user.HasChannel(channel) {
+		return entity.Result{}, nil
+	}
+
+	compiled, err := n.loadTemplate(notification)
+	if err != nil {
+		return entity.Result{}, err
+	}
+
+	result, err := n.send(notification, compiled, user, []entity.Channel{channel})
+	if err != nil {
+		return result, err
+	}
+
+	for i := range result.Failed {
+		if err := n.saveDeadLetter(notification, &result.Failed[i]); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// quietUntil reports whether the delivery has to wait for the user's quiet
+// hours to end. Only intrusive channels are held back, critical
+// notifications never are, and nothing is deferred without a schedule repository to keep
+// the delivery in.
+func (n NotificationUseCase) quietUntil(notification entity.Notification, user entity.User, channel entity.Channel) (time.Time, bool) {
+	if notification.IsCritical() || !channel.IsIntrusive() || n.ScheduleRepository == nil {
+		return time.Time{}, false
+	}
+
+	return user.QuietUntil(n.now())
+}
+
+// deferDelivery stores the delivery as a schedule for the user and channel,
+// sent by the scheduler at until.
+func (n NotificationUseCase) deferDelivery(notification entity.Notification, user entity.User, channel entity.Channel, until time.Time) error {
+	// The deferred delivery is sent by the scheduler, not the dispatcher.
+	notification.QueuedAt = time.Time{}
+	now := n.now()
+	return n.ScheduleRepository.SaveSchedule(entity.Schedule{
+		ID:           entity.NewID(),
+		Notification: notification,
+		UserID:       user.ID,
+		Channel:      channel,
+		SendAt:       until,
+		Status:       entity.ScheduleActive,
+		NextRun:      until,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+}
@@ -0,0 +1,114 @@
+package notification
+
+import (
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Midnight in São Paulo (UTC-3).
+var quietNow = time.Date(2024, 6, 3, 3, 0, 0, 0, time.UTC)
+
+func TestNotification_QuietHours_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service.ScheduleRepository = repositories.NewScheduleRepository(t.TempDir() + "/schedules.json")
+	service.now = func() time.Time { return quietNow }
+
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getQuietUser(1)}, nil)
+	logEntity.EXPECT().SaveLog(matchLog(getQuietMessage(1, "SMS", entity.LogDeferred))).Return(nil)
+	logEntity.EXPECT().SaveLog(matchLog(getQuietMessage(1, "E-Mail", entity.LogSent))).Return(nil)
+
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Logs))
+
+	schedules, err := service.ScheduleRepository.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(schedules))
+	assert.Equal(t, 1, schedules[0].UserID)
+	assert.Equal(t, entity.SMSChannel, schedules[0].Channel)
+	assert.Equal(t, entity.ScheduleActive, schedules[0].Status)
+	assert.True(t, time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC).Equal(schedules[0].NextRun))
+}
+
+func TestNotification_QuietHours_Critical_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service.ScheduleRepository = repositories.NewScheduleRepository(t.TempDir() + "/schedules.json")
+	service.now = func() time.Time { return quietNow }
+
+	notification := getNotification()
+	notification.Priority = entity.PriorityCritical
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getQuietUser(1)}, nil)
+	logEntity.EXPECT().SaveLog(matchLog(getQuietMessage(1, "SMS", entity.LogSent))).Return(nil)
+	logEntity.EXPECT().SaveLog(matchLog(getQuietMessage(1, "E-Mail", entity.LogSent))).Return(nil)
+
+	_, err := service.SendNotification(notification)
+	assert.NoError(t, err)
+
+	schedules, err := service.ScheduleRepository.GetSchedules()
+	assert.NoError(t, err)
+	assert.Empty(t, schedules)
+}
+
+func TestSendToUser_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service.ScheduleRepository = repositories.NewScheduleRepository(t.TempDir() + "/schedules.json")
+	service.now = func() time.Time { return quietNow.Add(7 * time.Hour) }
+
+	userEntity.EXPECT().GetUser(1).Return(getQuietUser(1), nil)
+	message := getQuietMessage(1, "SMS", entity.LogSent)
+	message.Timestamp = quietNow.Add(7 * time.Hour)
+	logEntity.EXPECT().SaveLog(matchLog(message)).Return(nil)
+
+	result, err := service.SendToUser(getNotification(), 1, entity.SMSChannel)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Logs))
+
+	unsubscribed := getQuietUser(2)
+	unsubscribed.Channels = []entity.Channel{entity.EmailChannel}
+	userEntity.EXPECT().GetUser(2).Return(unsubscribed, nil)
+	userEntity.EXPECT().GetUser(3).Return(entity.User{}, repositories.ErrUserNotFound)
+
+	result, err = service.SendToUser(getNotification(), 2, entity.SMSChannel)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Logs)
+
+	result, err = service.SendToUser(getNotification(), 3, entity.SMSChannel)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Logs)
+}
+
+func getQuietUser(id int) entity.User {
+	user := getUser(id)
+	user.Timezone = "America/Sao_Paulo"
+	user.QuietHours = &entity.QuietHours{Start: "22:00", End: "07:00"}
+	user.Channels = []entity.Channel{entity.SMSChannel, entity.EmailChannel}
+	return user
+}
+
+func getQuietMessage(id int, notificationType string, status entity.LogStatus) entity.Log {
+	message := getMessage(id, notificationType)
+	message.Status = status
+	message.Timestamp = quietNow
+	return message
+}
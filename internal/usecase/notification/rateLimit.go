@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"notification/internal/entity"
+	"notification/internal/usecase/notifiers"
+	"notification/internal/usecase/ratelimit"
+	"notification/internal/usecase/template"
+	"time"
+)
+
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
+// RateLimitPolicy decides what happens to a message over its channel's
+// limits.
+type RateLimitPolicy string
+
+const (
+	// DropPolicy doesn't send the message. In a fallback chain the next
+	// channel is tried.
+	DropPolicy RateLimitPolicy = "drop"
+	// DeferPolicy sends the message once the limits allow it again.
+	DeferPolicy RateLimitPolicy = "defer"
+	// DowngradePolicy sends the message on the Downgrade channel instead,
+	// unless the user already receives it there.
+	DowngradePolicy RateLimitPolicy = "downgrade"
+)
+
+// RateLimit limits a channel for every user on their own, User, and for the
+// channel's provider as a whole, Provider.
+type RateLimit struct {
+	User      ratelimit.Limit
+	Provider  ratelimit.Limit
+	Policy    RateLimitPolicy
+	Downgrade entity.Channel
+}
+
+// rateLimited takes a token for the delivery and reports whether it is over
+// the limits, with how long until it would be allowed. User limits count
+// messages, while the provider's throughput counts every SMS segment.
+// Critical notifications are not limited.
+func (n NotificationUseCase) rateLimited(notification entity.Notification, user entity.User, channel entity.Channel, content entity.Content) (RateLimit, time.Duration, bool) {
+	limit, ok := n.RateLimits[channel]
+	if !ok || notification.IsCritical() || n.RateLimiter == nil {
+		return limit, 0, false
+	}
+
+	parts := 1
+	if channel == entity.SMSChannel {
+		parts = len(notifiers.SplitMessage(content.Body))
+	}
+
+	allowed, wait := n.RateLimiter.Take(n.now(),
+		userRateLimit(user, channel, limit),
+		ratelimit.Request{Key: fmt.Sprintf("provider/%s", channel), Limit: limit.Provider, Tokens: parts},
+	)
+	return limit, wait, !allowed
+}
+
+// refundRateLimit gives back the user's token taken by rateLimited for a
+// delivery that failed, since the user didn't get the message. The
+// provider's tokens are kept: the failed attempts used its throughput.
+func (n NotificationUseCase) refundRateLimit(notification entity.Notification, user entity.User, channel entity.Channel) {
+	limit, ok := n.RateLimits[channel]
+	if !ok || notification.IsCritical() || n.RateLimiter == nil {
+		return
+	}
+
+	n.RateLimiter.Refund(n.now(), userRateLimit(user, channel, limit))
+}
+
+func userRateLimit(user entity.User, channel entity.Channel, limit RateLimit) ratelimit.Request {
+	return ratelimit.Request{Key: fmt.Sprintf("user/%v/%s", user.ID, channel), Limit: limit.User}
+}
+
+// overLimit applies the channel's policy to a message over its limits and
+// logs the outcome. It reports whether the message was handled, so that a
+// fallback chain stops.
+func (n NotificationUseCase) overLimit(notification entity.Notification, compiled *template.Compiled, user entity.User, channel entity.Channel, limit RateLimit, wait time.Duration, log entity.Log, result *entity.Result) (bool, error) {
+	log.Error = ErrRateLimitExceeded.Error()
+
+	switch {
+	case limit.Policy == DeferPolicy:
+		if err := n.deferDelivery(notification, user, channel, n.now().Add(wait)); err != nil {
+			return true, err
+		}
+		log.Status = entity.LogDeferred
+		return true, n.saveLog(notification, log, result)
+	case limit.Policy == DowngradePolicy && n.canDowngrade(user, limit.Downgrade):
+		log.Status = entity.LogDowngraded
+		if err := n.saveLog(notification, log, result); err != nil {
+			return true, err
+		}
+		return true, n.sendChain(notification, compiled, user, []entity.Channel{limit.Downgrade}, result)
+	default:
+		log.Status = entity.LogDropped
+		return false, n.saveLog(notification, log, result)
+	}
+}
+
+// canDowngrade reports whether a message can move to the channel: the user
+// has an address for it and doesn't already get the message there, and the
+// channel doesn't downgrade further.
+func (n NotificationUseCase) canDowngrade(user entity.User, channel entity.Channel) bool {
+	return !user.HasChannel(channel) &&
+		containsChannel(user.ReachableChannels(n.hasDevices(user)), channel) &&
+		n.RateLimits[channel].Policy != DowngradePolicy
+}
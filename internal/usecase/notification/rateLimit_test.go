@@ -0,0 +1,139 @@
+package notification
+
+import (
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/notifiers"
+	"notification/internal/usecase/ratelimit"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var limitNow = time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+
+func TestNotification_RateLimit_Drop_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := newLimitedService(controller, logEntity, userEntity, RateLimit{User: ratelimit.Limit{Count: 1, Per: time.Hour}, Policy: DropPolicy})
+
+	dropped := getLimitMessage(1, "SMS", entity.LogDropped)
+	dropped.Error = ErrRateLimitExceeded.Error()
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil).Times(3)
+	gomock.InOrder(
+		logEntity.EXPECT().SaveLog(matchLog(getLimitMessage(1, "SMS", entity.LogSent))).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(dropped)).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(getLimitMessage(1, "SMS", entity.LogSent))).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(getLimitMessage(1, "E-Mail", entity.LogSent))).Return(nil),
+	)
+
+	_, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Empty(t, result.Failed)
+
+	critical := getNotification()
+	critical.Priority = entity.PriorityCritical
+	_, err = service.SendNotification(critical)
+	assert.NoError(t, err)
+}
+
+func TestNotification_RateLimit_Defer_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := newLimitedService(controller, logEntity, userEntity, RateLimit{Provider: ratelimit.Limit{Count: 1, Per: time.Minute}, Policy: DeferPolicy})
+	service.ScheduleRepository = repositories.NewScheduleRepository(t.TempDir() + "/schedules.json")
+
+	deferred := getLimitMessage(2, "SMS", entity.LogDeferred)
+	deferred.Error = ErrRateLimitExceeded.Error()
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1), getUser(2)}, nil)
+	gomock.InOrder(
+		logEntity.EXPECT().SaveLog(matchLog(getLimitMessage(1, "SMS", entity.LogSent))).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(deferred)).Return(nil),
+	)
+
+	_, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+
+	schedules, err := service.ScheduleRepository.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(schedules))
+	assert.Equal(t, 2, schedules[0].UserID)
+	assert.Equal(t, limitNow.Add(time.Minute), schedules[0].NextRun)
+}
+
+func TestNotification_RateLimit_Downgrade_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	service := newLimitedService(controller, logEntity, userEntity, RateLimit{User: ratelimit.Limit{Count: 1, Per: time.Hour}, Policy: DowngradePolicy, Downgrade: entity.EmailChannel})
+
+	downgraded := getLimitMessage(1, "SMS", entity.LogDowngraded)
+	downgraded.Error = ErrRateLimitExceeded.Error()
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil).Times(2)
+	gomock.InOrder(
+		logEntity.EXPECT().SaveLog(matchLog(getLimitMessage(1, "SMS", entity.LogSent))).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(downgraded)).Return(nil),
+		logEntity.EXPECT().SaveLog(matchLog(getLimitMessage(1, "E-Mail", entity.LogSent))).Return(nil),
+	)
+
+	_, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Logs))
+}
+
+func TestNotification_RateLimit_Refund_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	userEntity := log.NewMockUser(controller)
+	deadLetterEntity := log.NewMockDeadLetter(controller)
+	service := newLimitedService(controller, logEntity, userEntity, RateLimit{User: ratelimit.Limit{Count: 1, Per: time.Hour}})
+	service.DeadLetterRepository = deadLetterEntity
+	service.SMSUsecase = notifiers.NewSMSUsecase(&smsProvider{errors: []error{notifiers.ErrInvalidNumber}}, "1")
+
+	userEntity.EXPECT().GetUsersByCategory(entity.SportsCategory).Return([]entity.User{getUser(1)}, nil).Times(2)
+	deadLetterEntity.EXPECT().SaveDeadLetter(gomock.Any()).Return(nil)
+	failed := getLimitMessage(1, "SMS", entity.LogFailed)
+	failed.Error = notifiers.ErrInvalidNumber.Error()
+	logEntity.EXPECT().SaveLog(matchLog(failed)).Return(nil)
+	logEntity.EXPECT().SaveLog(matchLog(getLimitMessage(1, "SMS", entity.LogSent))).Return(nil)
+
+	result, err := service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Failed))
+
+	// The failed delivery gave the user's token back.
+	result, err = service.SendNotification(getNotification())
+	assert.NoError(t, err)
+	assert.Empty(t, result.Failed)
+}
+
+func newLimitedService(controller *gomock.Controller, logEntity *log.MockLog, userEntity *log.MockUser, limit RateLimit) *NotificationUseCase {
+	service := NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service.RateLimits = map[entity.Channel]RateLimit{entity.SMSChannel: limit}
+	service.now = func() time.Time { return limitNow }
+	return service
+}
+
+func getLimitMessage(id int, notificationType string, status entity.LogStatus) entity.Log {
+	message := getMessage(id, notificationType)
+	message.Status = status
+	message.Timestamp = limitNow
+	return message
+}
@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+)
+
+// RecordReceipt applies a provider's receipt to the log of a delivery on the
+// channel and returns the log. A receipt that can't move the delivery from
+// its current status, such as one arriving after a later status, is ignored
+// and the stored log is returned unchanged. Receipts without a timestamp are
+// recorded at the time they are received.
+func (n NotificationUseCase) RecordReceipt(channel entity.Channel, receipt entity.Receipt) (entity.Log, error) {
+	notifier := n.getNotifier(channel)
+	if notifier == nil || receipt.LogID == "" {
+		return entity.Log{}, log.ErrLogNotFound
+	}
+
+	n.receipts.Lock()
+	defer n.receipts.Unlock()
+
+	logs, err := n.LogRepository.GetLogs(entity.LogFilter{ID: receipt.LogID, NotificationType: n.getNotificationType(notifier)})
+	if err != nil {
+		return entity.Log{}, err
+	}
+	if len(logs) == 0 {
+		return entity.Log{}, log.ErrLogNotFound
+	}
+
+	timestamp := receipt.Timestamp
+	if timestamp.IsZero() {
+		timestamp = n.now()
+	}
+
+	updated, ok := logs[0].Transition(receipt.Status, timestamp)
+	if !ok {
+		return logs[0], nil
+	}
+	if receipt.Error != "" {
+		updated.Error = receipt.Error
+	}
+
+	return updated, n.LogRepository.UpdateLog(updated)
+}
@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordReceipt_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), log.NewMockDeadLetter(controller))
+
+	sent := getMessage(1, "SMS")
+	sent.ID = "log"
+	delivered := time.Now().Add(time.Minute)
+	filter := entity.LogFilter{ID: "log", NotificationType: "SMS"}
+	logEntity.EXPECT().GetLogs(filter).Return([]entity.Log{sent}, nil)
+	logEntity.EXPECT().UpdateLog(gomock.Any()).Return(nil)
+
+	updated, err := service.RecordReceipt(entity.SMSChannel, entity.Receipt{LogID: "log", Status: entity.LogDelivered, Timestamp: delivered})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.LogDelivered, updated.Status)
+	assert.Equal(t, []entity.StatusChange{
+		{Status: entity.LogSent, Timestamp: sent.Timestamp},
+		{Status: entity.LogDelivered, Timestamp: delivered},
+	}, updated.History)
+}
+
+func TestRecordReceipt_Bounced_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), log.NewMockDeadLetter(controller))
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	delivered := getMessage(1, "E-Mail")
+	delivered.ID = "log"
+	delivered, _ = delivered.Transition(entity.LogDelivered, delivered.Timestamp)
+	logEntity.EXPECT().GetLogs(entity.LogFilter{ID: "log", NotificationType: "E-Mail"}).Return([]entity.Log{delivered}, nil)
+	logEntity.EXPECT().UpdateLog(gomock.Any()).DoAndReturn(func(updated entity.Log) error {
+		assert.Equal(t, entity.LogBounced, updated.Status)
+		assert.Equal(t, "mailbox full", updated.Error)
+		assert.Equal(t, 3, len(updated.History))
+		assert.Equal(t, now, updated.History[2].Timestamp)
+		return nil
+	})
+
+	_, err := service.RecordReceipt(entity.EmailChannel, entity.Receipt{LogID: "log", Status: entity.LogBounced, Error: "mailbox full"})
+	assert.NoError(t, err)
+}
+
+func TestRecordReceipt_OutOfOrder_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), log.NewMockDeadLetter(controller))
+
+	opened := getMessage(1, "Push Notification")
+	opened.ID = "log"
+	opened, _ = opened.Transition(entity.LogOpened, opened.Timestamp)
+	logEntity.EXPECT().GetLogs(gomock.Any()).Return([]entity.Log{opened}, nil).Times(2)
+
+	for _, status := range []entity.LogStatus{entity.LogDelivered, entity.LogOpened} {
+		unchanged, err := service.RecordReceipt(entity.PushChannel, entity.Receipt{LogID: "log", Status: status})
+		assert.NoError(t, err)
+		assert.Equal(t, opened, unchanged)
+	}
+}
+
+func TestRecordReceipt_NotFound_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	service := NewNotificationUseCase(logEntity, log.NewMockUser(controller), log.NewMockDeadLetter(controller))
+
+	logEntity.EXPECT().GetLogs(entity.LogFilter{ID: "missing", NotificationType: "SMS"}).Return([]entity.Log{}, nil)
+
+	_, err := service.RecordReceipt(entity.SMSChannel, entity.Receipt{LogID: "missing", Status: entity.LogDelivered})
+	assert.ErrorIs(t, err, repositories.ErrLogNotFound)
+
+	_, err = service.RecordReceipt(entity.SMSChannel, entity.Receipt{Status: entity.LogDelivered})
+	assert.ErrorIs(t, err, repositories.ErrLogNotFound)
+}
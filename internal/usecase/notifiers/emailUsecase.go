@@ -1,14 +1,251 @@
 package notifiers
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
 	"notification/internal/entity"
+	"strconv"
+	"strings"
+	"time"
 )
 
-type EmailUsecase struct{}
+type TLSMode string
+
+const (
+	TLSNone     TLSMode = "none"
+	TLSStart    TLSMode = "starttls"
+	TLSImplicit TLSMode = "tls"
+)
+
+const defaultSubject = "New notification"
+
+// ReferenceHeader carries the content's Reference, for providers that pass
+// custom headers back with their events.
+const ReferenceHeader = "X-Log-ID"
+
+var ErrNoEmail = errors.New("user has no email address")
+
+type EmailConfig struct {
+	Host      string
+	Port      int
+	Username  string
+	Password  string
+	From      string
+	Subject   string
+	TLS       TLSMode
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+}
+
+type EmailUsecase struct {
+	Config EmailConfig
+}
+
+func NewEmailUsecase(config EmailConfig) *EmailUsecase {
+	return &EmailUsecase{
+		Config: config,
+	}
+}
 
 func (s *EmailUsecase) SendNotification(user entity.User, message string) error {
-	fmt.Printf("Sending email notification to %s (%s): %s\n", user.Name, user.Email, message)
-	// TODO: Logic to send email notification
-	return nil
+	return s.SendContent(context.Background(), user, entity.Content{Body: message})
+}
+
+func (s *EmailUsecase) SendContent(ctx context.Context, user entity.User, content entity.Content) error {
+	if s.Config.Host == "" {
+		fmt.Printf("Sending email notification to %s (%s): %s\n", user.Name, user.Email, content.Body)
+		return nil
+	}
+
+	if user.Email == "" {
+		return fmt.Errorf("%w: user %v", ErrNoEmail, user.ID)
+	}
+
+	message, err := s.buildMessage(user, content)
+	if err != nil {
+		return err
+	}
+
+	return s.deliver(ctx, user.Email, message)
+}
+
+func (s *EmailUsecase) buildMessage(user entity.User, message entity.Content) ([]byte, error) {
+	subject := message.Subject
+	if subject == "" {
+		subject = s.Config.Subject
+	}
+	if subject == "" {
+		subject = defaultSubject
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	if err := writePart(parts, "text/plain; charset=utf-8", message.Body); err != nil {
+		return nil, err
+	}
+
+	htmlMessage := message.HTML
+	if htmlMessage == "" {
+		htmlMessage = "<html><body><p>" + strings.ReplaceAll(html.EscapeString(message.Body), "\n", "<br>") + "</p></body></html>"
+	}
+	if err := writePart(parts, "text/html; charset=utf-8", htmlMessage); err != nil {
+		return nil, err
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	to := user.Email
+	if user.Name != "" {
+		to = mime.QEncoding.Encode("utf-8", user.Name) + " <" + user.Email + ">"
+	}
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "From: %s\r\n", s.Config.From)
+	fmt.Fprintf(&content, "To: %s\r\n", to)
+	fmt.Fprintf(&content, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&content, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	if message.Reference != "" {
+		fmt.Fprintf(&content, "%s: %s\r\n", ReferenceHeader, message.Reference)
+	}
+	fmt.Fprintf(&content, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&content, "Content-Type: multipart/alternative; boundary=%q\r\n", parts.Boundary())
+	fmt.Fprintf(&content, "\r\n")
+	content.Write(body.Bytes())
+
+	return content.Bytes(), nil
+}
+
+// deliver runs the SMTP session. When ctx is done the connection is closed,
+// which aborts the session before the message is accepted.
+func (s *EmailUsecase) deliver(ctx context.Context, to string, content []byte) error {
+	address := net.JoinHostPort(s.Config.Host, strconv.Itoa(s.port()))
+	dialer := &net.Dialer{Timeout: s.timeout()}
+
+	var conn net.Conn
+	var err error
+	if s.Config.TLS == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tlsConfig()}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(s.timeout()))
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	client, err := smtp.NewClient(conn, s.Config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("Failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if s.Config.TLS == TLSStart {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(s.tlsConfig()); err != nil {
+			return fmt.Errorf("Failed to start TLS: %w", err)
+		}
+	}
+
+	if s.Config.Username != "" {
+		auth := smtp.PlainAuth("", s.Config.Username, s.Config.Password, s.Config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("Failed to authenticate on SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.Config.From); err != nil {
+		return fmt.Errorf("Failed to set sender: %w", err)
+	}
+
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("Failed to set recipient: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("Failed to start message data: %w", err)
+	}
+
+	if _, err := writer.Write(content); err != nil {
+		return fmt.Errorf("Failed to write message: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("Failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (s *EmailUsecase) port() int {
+	if s.Config.Port != 0 {
+		return s.Config.Port
+	}
+
+	switch s.Config.TLS {
+	case TLSImplicit:
+		return 465
+	case TLSStart:
+		return 587
+	default:
+		return 25
+	}
+}
+
+func (s *EmailUsecase) timeout() time.Duration {
+	if s.Config.Timeout != 0 {
+		return s.Config.Timeout
+	}
+	return 30 * time.Second
+}
+
+func (s *EmailUsecase) tlsConfig() *tls.Config {
+	if s.Config.TLSConfig != nil {
+		return s.Config.TLSConfig
+	}
+	return &tls.Config{ServerName: s.Config.Host}
+}
+
+func writePart(parts *multipart.Writer, contentType string, content string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := parts.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	writer := quotedprintable.NewWriter(part)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return writer.Close()
 }
@@ -1,8 +1,19 @@
 package notifiers
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
 	"notification/internal/entity"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,3 +24,188 @@ func TestEmail_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 }
+
+func TestEmail_SMTP_Success(t *testing.T) {
+	server := startSMTPServer(t, "250")
+	service := NewEmailUsecase(EmailConfig{
+		Host:     "127.0.0.1",
+		Port:     server.port,
+		Username: "user",
+		Password: "secret",
+		From:     "notifications@example.com",
+		Subject:  "Finance news",
+		TLS:      TLSNone,
+		Timeout:  time.Second,
+	})
+
+	user := entity.User{Name: "Antony Smith", Email: "antony.smith@gmail.com"}
+	err := service.SendNotification(user, "Markets are <up> today")
+	assert.NoError(t, err)
+
+	message := <-server.messages
+	assert.Equal(t, "<notifications@example.com>", message.from)
+	assert.Equal(t, "<antony.smith@gmail.com>", message.to)
+	assert.True(t, message.authenticated)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(message.data))
+	assert.NoError(t, err)
+	assert.Equal(t, "Finance news", parsed.Header.Get("Subject"))
+	assert.Equal(t, "1.0", parsed.Header.Get("MIME-Version"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	bodies := map[string]string{}
+	for {
+		part, err := parts.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		assert.NoError(t, err)
+		bodies[part.Header.Get("Content-Type")] = string(content)
+	}
+
+	assert.Equal(t, "Markets are <up> today", bodies["text/plain; charset=utf-8"])
+	assert.Contains(t, bodies["text/html; charset=utf-8"], "Markets are &lt;up&gt; today")
+}
+
+func TestEmail_Content_Success(t *testing.T) {
+	server := startSMTPServer(t, "250")
+	service := NewEmailUsecase(EmailConfig{
+		Host:    "127.0.0.1",
+		Port:    server.port,
+		From:    "notifications@example.com",
+		Subject: "Finance news",
+		TLS:     TLSNone,
+		Timeout: time.Second,
+	})
+
+	user := entity.User{Name: "Antony Smith", Email: "antony.smith@gmail.com"}
+	err := service.SendContent(context.Background(), user, entity.Content{Subject: "Markets closed", Body: "Markets closed up", HTML: "<h1>Markets closed up</h1>", Reference: "0190abc"})
+	assert.NoError(t, err)
+
+	message := <-server.messages
+	parsed, err := mail.ReadMessage(strings.NewReader(message.data))
+	assert.NoError(t, err)
+	assert.Equal(t, "Markets closed", parsed.Header.Get("Subject"))
+	assert.Equal(t, "0190abc", parsed.Header.Get(ReferenceHeader))
+
+	body, err := io.ReadAll(parsed.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "<h1>Markets closed up</h1>")
+}
+
+func TestEmail_SMTP_Error(t *testing.T) {
+	server := startSMTPServer(t, "550")
+	service := NewEmailUsecase(EmailConfig{
+		Host:    "127.0.0.1",
+		Port:    server.port,
+		From:    "notifications@example.com",
+		Timeout: time.Second,
+	})
+
+	err := service.SendNotification(entity.User{Name: "Antony Smith", Email: "antony.smith@gmail.com"}, "test function")
+	assert.Error(t, err)
+
+	err = service.SendNotification(entity.User{Name: "Antony Smith"}, "test function")
+	assert.Error(t, err)
+}
+
+type smtpMessage struct {
+	from          string
+	to            string
+	data          string
+	authenticated bool
+}
+
+type smtpServer struct {
+	port     int
+	messages chan smtpMessage
+}
+
+// startSMTPServer runs a minimal in-process SMTP stand-in that answers RCPT
+// with rcptCode and records every accepted message.
+func startSMTPServer(t *testing.T, rcptCode string) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &smtpServer{
+		port:     listener.Addr().(*net.TCPAddr).Port,
+		messages: make(chan smtpMessage, 10),
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.handle(conn, rcptCode)
+		}
+	}()
+
+	return server
+}
+
+func (s *smtpServer) handle(conn net.Conn, rcptCode string) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+	message := smtpMessage{}
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "AUTH PLAIN"):
+			message.authenticated = true
+			reply("235 Authenticated")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			message.from = line[len("MAIL FROM:"):]
+			if index := strings.Index(message.from, " "); index >= 0 {
+				message.from = message.from[:index]
+			}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			message.to = line[len("RCPT TO:"):]
+			reply(rcptCode + " Recipient")
+		case command == "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			message.data = data.String()
+			s.messages <- message
+			reply("250 Queued")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
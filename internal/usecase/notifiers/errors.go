@@ -0,0 +1,38 @@
+package notifiers
+
+import (
+	"errors"
+	"net/textproto"
+)
+
+// permanentErrors can't be fixed by sending the same message again.
+var permanentErrors = []error{
+	ErrNoEmail,
+	ErrInvalidNumber,
+	ErrUnauthorized,
+	ErrRejected,
+	ErrNoDevices,
+	ErrInvalidToken,
+	ErrPushRejected,
+}
+
+// IsRetryable reports whether a failed delivery may succeed on a later
+// attempt. Errors that aren't known to be permanent are treated as transient.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, permanent := range permanentErrors {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) {
+		return smtpErr.Code < 500
+	}
+
+	return true
+}
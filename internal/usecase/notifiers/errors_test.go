@@ -0,0 +1,23 @@
+package notifiers
+
+import (
+	"errors"
+	"fmt"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(fmt.Errorf("%w: +123", ErrInvalidNumber)))
+	assert.False(t, IsRetryable(&GatewayError{StatusCode: 401, Err: ErrUnauthorized}))
+	assert.False(t, IsRetryable(&PushError{StatusCode: 400, Err: ErrPushRejected}))
+	assert.False(t, IsRetryable(fmt.Errorf("Failed to set recipient: %w", &textproto.Error{Code: 550, Msg: "No such user"})))
+
+	assert.True(t, IsRetryable(&GatewayError{StatusCode: 429, Err: ErrRateLimited}))
+	assert.True(t, IsRetryable(&PushError{StatusCode: 503, Err: ErrPushUnavailable}))
+	assert.True(t, IsRetryable(fmt.Errorf("Failed to set recipient: %w", &textproto.Error{Code: 451, Msg: "Try again later"})))
+	assert.True(t, IsRetryable(errors.New("connection reset")))
+}
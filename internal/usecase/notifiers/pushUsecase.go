@@ -1,14 +1,215 @@
 package notifiers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"time"
 )
 
-type PushUsecase struct{}
+var (
+	ErrNoDevices       = errors.New("user has no registered devices")
+	ErrInvalidToken    = errors.New("device token is no longer valid")
+	ErrPushRejected    = errors.New("push service rejected message")
+	ErrPushUnavailable = errors.New("push service unavailable")
+)
+
+const defaultTitle = "New notification"
+
+type PushConfig struct {
+	Endpoint    string
+	AccessToken string
+	Title       string
+	Timeout     time.Duration
+}
+
+type PushUsecase struct {
+	Config           PushConfig
+	DeviceRepository log.Device
+	Client           *http.Client
+}
+
+type PushError struct {
+	StatusCode int
+	Status     string
+	ErrorCode  string
+	Message    string
+	Err        error
+}
+
+type pushMessage struct {
+	Message pushTarget `json:"message"`
+}
+
+type pushTarget struct {
+	Token        string            `json:"token"`
+	Notification pushContent       `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type pushContent struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func (e *PushError) Error() string {
+	return fmt.Sprintf("%v: status %d %s %s: %s", e.Err, e.StatusCode, e.Status, e.ErrorCode, e.Message)
+}
+
+func (e *PushError) Unwrap() error {
+	return e.Err
+}
+
+func NewPushUsecase(config PushConfig, device log.Device) *PushUsecase {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &PushUsecase{
+		Config:           config,
+		DeviceRepository: device,
+		Client:           &http.Client{Timeout: timeout},
+	}
+}
 
 func (s *PushUsecase) SendNotification(user entity.User, message string) error {
-	fmt.Printf("Sending push notification to %s: %s\n", user.Name, message)
-	// TODO:Logic to send push notification
+	return s.SendContent(context.Background(), user, entity.Content{Body: message})
+}
+
+func (s *PushUsecase) SendContent(ctx context.Context, user entity.User, content entity.Content) error {
+	if s.Config.Endpoint == "" {
+		fmt.Printf("Sending push notification to %s: %s\n", user.Name, content.Body)
+		return nil
+	}
+
+	devices, err := s.DeviceRepository.GetDevicesByUser(user.ID)
+	if err != nil {
+		return err
+	}
+
+	delivered := 0
+	lastErr := ErrNoDevices
+	for _, device := range devices {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := s.push(ctx, device, content)
+		if errors.Is(err, ErrInvalidToken) {
+			if err := s.DeviceRepository.DeleteDevice(device.Token); err != nil && !errors.Is(err, log.ErrDeviceNotFound) {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 {
+		return lastErr
+	}
+
 	return nil
 }
+
+func (s *PushUsecase) push(ctx context.Context, device entity.Device, content entity.Content) error {
+	title := content.Title
+	if title == "" {
+		title = s.Config.Title
+	}
+	if title == "" {
+		title = defaultTitle
+	}
+
+	payload, err := json.Marshal(pushMessage{
+		Message: pushTarget{
+			Token: device.Token,
+			Notification: pushContent{
+				Title: title,
+				Body:  content.Body,
+			},
+			Data: pushData(device, content),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Config.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if s.Config.AccessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+s.Config.AccessToken)
+	}
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return &PushError{Message: err.Error(), Err: ErrPushUnavailable}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	return pushError(response)
+}
+
+// pushData tells the app the device's platform and, as log_id, the
+// content's Reference, which the app reports opened receipts with.
+func pushData(device entity.Device, content entity.Content) map[string]string {
+	data := map[string]string{
+		"platform": string(device.Platform),
+	}
+	if content.Reference != "" {
+		data["log_id"] = content.Reference
+	}
+
+	return data
+}
+
+func pushError(response *http.Response) error {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	json.NewDecoder(response.Body).Decode(&body)
+
+	pushErr := &PushError{
+		StatusCode: response.StatusCode,
+		Status:     body.Error.Status,
+		Message:    body.Error.Message,
+	}
+	for _, detail := range body.Error.Details {
+		if detail.ErrorCode != "" {
+			pushErr.ErrorCode = detail.ErrorCode
+		}
+	}
+
+	switch {
+	case pushErr.ErrorCode == "UNREGISTERED" || response.StatusCode == http.StatusNotFound:
+		pushErr.Err = ErrInvalidToken
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		pushErr.Err = ErrPushUnavailable
+	default:
+		pushErr.Err = ErrPushRejected
+	}
+
+	return pushErr
+}
@@ -1,9 +1,15 @@
 package notifiers
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"notification/internal/entity"
+	log "notification/test/platform"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,3 +19,69 @@ func TestPush_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 }
+
+func TestPush_Devices_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	deviceEntity := log.NewMockDevice(controller)
+
+	var received []pushMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var message pushMessage
+		json.NewDecoder(r.Body).Decode(&message)
+		received = append(received, message)
+
+		if message.Message.Token == "stale" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"code": 404, "message": "Requested entity was not found.", "status": "NOT_FOUND", "details": [{"errorCode": "UNREGISTERED"}]}}`))
+			return
+		}
+
+		w.Write([]byte(`{"name": "projects/demo/messages/1"}`))
+	}))
+	defer server.Close()
+
+	service := NewPushUsecase(PushConfig{Endpoint: server.URL, AccessToken: "secret", Title: "Finance"}, deviceEntity)
+
+	deviceEntity.EXPECT().GetDevicesByUser(2).Return([]entity.Device{
+		{Token: "phone", UserID: 2, Platform: entity.IOSPlatform},
+		{Token: "stale", UserID: 2, Platform: entity.AndroidPlatform},
+		{Token: "browser", UserID: 2, Platform: entity.WebPlatform},
+	}, nil)
+	deviceEntity.EXPECT().DeleteDevice("stale").Return(nil)
+
+	err := service.SendContent(context.Background(), entity.User{ID: 2}, entity.Content{Body: "test function", Reference: "0190abc"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, len(received))
+	assert.Equal(t, "phone", received[0].Message.Token)
+	assert.Equal(t, "Finance", received[0].Message.Notification.Title)
+	assert.Equal(t, "test function", received[0].Message.Notification.Body)
+	assert.Equal(t, "iOS", received[0].Message.Data["platform"])
+	assert.Equal(t, "0190abc", received[0].Message.Data["log_id"])
+}
+
+func TestPush_Devices_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	deviceEntity := log.NewMockDevice(controller)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	service := NewPushUsecase(PushConfig{Endpoint: server.URL}, deviceEntity)
+
+	deviceEntity.EXPECT().GetDevicesByUser(2).Return(nil, nil)
+	err := service.SendNotification(entity.User{ID: 2}, "test function")
+	assert.ErrorIs(t, err, ErrNoDevices)
+
+	deviceEntity.EXPECT().GetDevicesByUser(2).Return([]entity.Device{{Token: "phone", UserID: 2}}, nil)
+	err = service.SendNotification(entity.User{ID: 2}, "test function")
+	assert.ErrorIs(t, err, ErrPushUnavailable)
+}
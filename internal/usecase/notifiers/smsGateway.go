@@ -0,0 +1,146 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidNumber      = errors.New("invalid phone number")
+	ErrUnauthorized       = errors.New("gateway rejected credentials")
+	ErrRateLimited        = errors.New("gateway rate limit exceeded")
+	ErrGatewayUnavailable = errors.New("gateway unavailable")
+	ErrRejected           = errors.New("gateway rejected message")
+)
+
+// Error codes the gateway uses to flag an unusable destination number.
+var invalidNumberCodes = map[int]bool{
+	21211: true,
+	21214: true,
+	21614: true,
+}
+
+// SMSProvider sends one SMS, which it splits into segments when it is too
+// long for one. It gives up when ctx is done. The
+// reference, when given, identifies the delivery in the provider's status
+// callbacks.
+type SMSProvider interface {
+	SendSMS(ctx context.Context, to string, body string, reference string) error
+}
+
+// SMSGatewayConfig configures the gateway. StatusCallback, when set, is the
+// URL the gateway posts status updates to, with the message's reference
+// added as the log_id query parameter.
+type SMSGatewayConfig struct {
+	BaseURL        string
+	AccountID      string
+	AuthToken      string
+	From           string
+	StatusCallback string
+	Timeout        time.Duration
+}
+
+type HTTPGateway struct {
+	Config SMSGatewayConfig
+	Client *http.Client
+}
+
+type GatewayError struct {
+	StatusCode int
+	Code       int
+	Message    string
+	Err        error
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("%v: status %d, code %d: %s", e.Err, e.StatusCode, e.Code, e.Message)
+}
+
+func (e *GatewayError) Unwrap() error {
+	return e.Err
+}
+
+func NewHTTPGateway(config SMSGatewayConfig) *HTTPGateway {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &HTTPGateway{
+		Config: config,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (g *HTTPGateway) SendSMS(ctx context.Context, to string, body string, reference string) error {
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", g.Config.From)
+	form.Set("Body", body)
+	if g.Config.StatusCallback != "" && reference != "" {
+		callback, err := url.Parse(g.Config.StatusCallback)
+		if err != nil {
+			return err
+		}
+		query := callback.Query()
+		query.Set("log_id", reference)
+		callback.RawQuery = query.Encode()
+		form.Set("StatusCallback", callback.String())
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", strings.TrimRight(g.Config.BaseURL, "/"), url.PathEscape(g.Config.AccountID))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(g.Config.AccountID, g.Config.AuthToken)
+
+	response, err := g.Client.Do(request)
+	if err != nil {
+		return &GatewayError{Message: err.Error(), Err: ErrGatewayUnavailable}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+
+	return gatewayError(response)
+}
+
+func gatewayError(response *http.Response) error {
+	var body struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	json.NewDecoder(response.Body).Decode(&body)
+
+	gatewayErr := &GatewayError{
+		StatusCode: response.StatusCode,
+		Code:       body.Code,
+		Message:    body.Message,
+	}
+
+	switch {
+	case invalidNumberCodes[body.Code]:
+		gatewayErr.Err = ErrInvalidNumber
+	case response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden:
+		gatewayErr.Err = ErrUnauthorized
+	case response.StatusCode == http.StatusTooManyRequests:
+		gatewayErr.Err = ErrRateLimited
+	case response.StatusCode >= 500:
+		gatewayErr.Err = ErrGatewayUnavailable
+	default:
+		gatewayErr.Err = ErrRejected
+	}
+
+	return gatewayErr
+}
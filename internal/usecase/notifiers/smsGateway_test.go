@@ -0,0 +1,83 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGateway_Success(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+
+		account, token, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", account)
+		assert.Equal(t, "secret", token)
+
+		r.ParseForm()
+		received = r.PostForm
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"sid": "SM1", "status": "queued"})
+	}))
+	defer server.Close()
+
+	config := getGatewayConfig(server.URL + "/2010-04-01")
+	config.StatusCallback = "https://notify.example.com/webhooks/sms"
+	gateway := NewHTTPGateway(config)
+	err := gateway.SendSMS(context.Background(), "+5511999999999", "test function", "0190abc")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "+5511999999999", received.Get("To"))
+	assert.Equal(t, "+15005550006", received.Get("From"))
+	assert.Equal(t, "test function", received.Get("Body"))
+	assert.Equal(t, "https://notify.example.com/webhooks/sms?log_id=0190abc", received.Get("StatusCallback"))
+}
+
+func TestGateway_Error(t *testing.T) {
+	tests := []struct {
+		status int
+		code   int
+		err    error
+	}{
+		{status: http.StatusBadRequest, code: 21211, err: ErrInvalidNumber},
+		{status: http.StatusUnauthorized, code: 20003, err: ErrUnauthorized},
+		{status: http.StatusTooManyRequests, code: 20429, err: ErrRateLimited},
+		{status: http.StatusServiceUnavailable, code: 0, err: ErrGatewayUnavailable},
+		{status: http.StatusBadRequest, code: 21602, err: ErrRejected},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": test.code, "message": "failure", "status": test.status})
+		}))
+
+		gateway := NewHTTPGateway(getGatewayConfig(server.URL))
+		err := gateway.SendSMS(context.Background(), "+5511999999999", "test function", "")
+		assert.ErrorIs(t, err, test.err)
+
+		var gatewayErr *GatewayError
+		assert.ErrorAs(t, err, &gatewayErr)
+		assert.Equal(t, test.status, gatewayErr.StatusCode)
+		assert.Equal(t, test.code, gatewayErr.Code)
+
+		server.Close()
+	}
+}
+
+func getGatewayConfig(baseURL string) SMSGatewayConfig {
+	return SMSGatewayConfig{
+		BaseURL:   baseURL,
+		AccountID: "AC123",
+		AuthToken: "secret",
+		From:      "+15005550006",
+	}
+}
@@ -0,0 +1,120 @@
+package notifiers
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+type SMSEncoding string
+
+const (
+	GSM7 SMSEncoding = "GSM-7"
+	UCS2 SMSEncoding = "UCS-2"
+)
+
+const (
+	gsm7SingleLength = 160
+	gsm7PartLength   = 153
+	ucs2SingleLength = 70
+	ucs2PartLength   = 67
+)
+
+const (
+	gsm7Basic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "\f^{}\\[~]|€"
+)
+
+// NormalizePhoneNumber converts a phone number to E.164. Numbers without an
+// international prefix are assumed to belong to defaultCountryCode.
+func NormalizePhoneNumber(number string, defaultCountryCode string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '/':
+			return -1
+		}
+		return r
+	}, number)
+
+	var digits string
+	switch {
+	case strings.HasPrefix(cleaned, "+"):
+		digits = cleaned[1:]
+	case strings.HasPrefix(cleaned, "00"):
+		digits = cleaned[2:]
+	case defaultCountryCode != "":
+		digits = strings.TrimPrefix(defaultCountryCode, "+") + strings.TrimLeft(cleaned, "0")
+	default:
+		return "", fmt.Errorf("%w: %q has no country code", ErrInvalidNumber, number)
+	}
+
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNumber, number)
+	}
+
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidNumber, number)
+		}
+	}
+
+	return "+" + digits, nil
+}
+
+// MessageEncoding returns GSM-7 when every character fits the GSM 03.38
+// alphabet and UCS-2 otherwise.
+func MessageEncoding(message string) SMSEncoding {
+	for _, r := range message {
+		if !strings.ContainsRune(gsm7Basic, r) && !strings.ContainsRune(gsm7Extended, r) {
+			return UCS2
+		}
+	}
+	return GSM7
+}
+
+// SplitMessage splits a message into the segments the provider sends it as,
+// without breaking escape sequences or surrogate pairs. Their number is what
+// the message is billed and rate limited as.
+func SplitMessage(message string) []string {
+	if MessageEncoding(message) == GSM7 {
+		return splitSegments(message, gsm7SingleLength, gsm7PartLength, gsm7Length)
+	}
+	return splitSegments(message, ucs2SingleLength, ucs2PartLength, ucs2Length)
+}
+
+func splitSegments(message string, singleLength int, partLength int, length func(rune) int) []string {
+	total := 0
+	for _, r := range message {
+		total += length(r)
+	}
+
+	if total <= singleLength {
+		return []string{message}
+	}
+
+	var segments []string
+	var segment strings.Builder
+	size := 0
+	for _, r := range message {
+		if size+length(r) > partLength {
+			segments = append(segments, segment.String())
+			segment.Reset()
+			size = 0
+		}
+		segment.WriteRune(r)
+		size += length(r)
+	}
+
+	return append(segments, segment.String())
+}
+
+func gsm7Length(r rune) int {
+	if strings.ContainsRune(gsm7Extended, r) {
+		return 2
+	}
+	return 1
+}
+
+func ucs2Length(r rune) int {
+	return len(utf16.Encode([]rune{r}))
+}
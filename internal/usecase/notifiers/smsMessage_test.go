@@ -0,0 +1,69 @@
+package notifiers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhoneNumber_Success(t *testing.T) {
+	tests := map[string]string{
+		"+1 (415) 555-2671": "+14155552671",
+		"0044 20 7946 0958": "+442079460958",
+		"4134132441":        "+14134132441",
+		"078958745":         "+178958745",
+	}
+
+	for number, expected := range tests {
+		normalized, err := NormalizePhoneNumber(number, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, normalized)
+	}
+}
+
+func TestNormalizePhoneNumber_Error(t *testing.T) {
+	for _, number := range []string{"", "+123", "+1234567890123456", "+1415abc2671", "+0415555267"} {
+		_, err := NormalizePhoneNumber(number, "1")
+		assert.ErrorIs(t, err, ErrInvalidNumber)
+	}
+
+	_, err := NormalizePhoneNumber("4134132441", "")
+	assert.ErrorIs(t, err, ErrInvalidNumber)
+}
+
+func TestSplitMessage_GSM7(t *testing.T) {
+	assert.Equal(t, GSM7, MessageEncoding("Match starts at 20:00 @ stadium"))
+	assert.Equal(t, []string{"short"}, SplitMessage("short"))
+
+	single := strings.Repeat("a", 160)
+	assert.Equal(t, []string{single}, SplitMessage(single))
+
+	segments := SplitMessage(strings.Repeat("a", 161))
+	assert.Equal(t, 2, len(segments))
+	assert.Equal(t, 153, len(segments[0]))
+	assert.Equal(t, 8, len(segments[1]))
+
+	// Extended characters take two septets and are never split.
+	segments = SplitMessage(strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10))
+	assert.Equal(t, 2, len(segments))
+	assert.Equal(t, strings.Repeat("a", 152), segments[0])
+	assert.True(t, strings.HasPrefix(segments[1], "€"))
+}
+
+func TestSplitMessage_UCS2(t *testing.T) {
+	assert.Equal(t, UCS2, MessageEncoding("Olá, você ganhou 🎉"))
+
+	single := strings.Repeat("ç", 70)
+	assert.Equal(t, []string{single}, SplitMessage(single))
+
+	segments := SplitMessage(strings.Repeat("ç", 71))
+	assert.Equal(t, 2, len(segments))
+	assert.Equal(t, 67, len([]rune(segments[0])))
+
+	// Emoji take two UTF-16 code units and stay together.
+	segments = SplitMessage(strings.Repeat("ç", 66) + strings.Repeat("🎉", 3))
+	assert.Equal(t, 2, len(segments))
+	assert.Equal(t, strings.Repeat("ç", 66), segments[0])
+	assert.Equal(t, strings.Repeat("🎉", 3), segments[1])
+}
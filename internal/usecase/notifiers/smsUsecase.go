@@ -1,14 +1,39 @@
 package notifiers
 
 import (
+	"context"
 	"fmt"
 	"notification/internal/entity"
 )
 
-type SMSUsecase struct{}
+type SMSUsecase struct {
+	Provider           SMSProvider
+	DefaultCountryCode string
+}
+
+func NewSMSUsecase(provider SMSProvider, defaultCountryCode string) *SMSUsecase {
+	return &SMSUsecase{
+		Provider:           provider,
+		DefaultCountryCode: defaultCountryCode,
+	}
+}
 
 func (s *SMSUsecase) SendNotification(user entity.User, message string) error {
-	fmt.Printf("Sending SMS notification to %s (%s): %s\n", user.Name, user.PhoneNumber, message)
-	// TODO: Logic to send SMS notification
-	return nil
+	return s.SendContent(context.Background(), user, entity.Content{Body: message})
+}
+
+func (s *SMSUsecase) SendContent(ctx context.Context, user entity.User, content entity.Content) error {
+	message := content.Body
+	if s.Provider == nil {
+		fmt.Printf("Sending SMS notification to %s (%s): %s\n", user.Name, user.PhoneNumber, message)
+		return nil
+	}
+
+	number, err := NormalizePhoneNumber(user.PhoneNumber, s.DefaultCountryCode)
+	if err != nil {
+		return err
+	}
+
+	// The provider splits a long message into concatenated segments itself.
+	return s.Provider.SendSMS(ctx, number, message, content.Reference)
 }
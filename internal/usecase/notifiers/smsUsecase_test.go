@@ -1,7 +1,10 @@
 package notifiers
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"notification/internal/entity"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,3 +16,34 @@ func TestSMS_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 }
+
+func TestSMS_Gateway_Success(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assert.Equal(t, "+178958745", r.PostForm.Get("To"))
+		bodies = append(bodies, r.PostForm.Get("Body"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	service := NewSMSUsecase(NewHTTPGateway(getGatewayConfig(server.URL)), "1")
+	err := service.SendNotification(entity.User{PhoneNumber: "78958745"}, strings.Repeat("a", 200))
+	assert.NoError(t, err)
+	// A long message goes out whole; the gateway segments it.
+	assert.Equal(t, []string{strings.Repeat("a", 200)}, bodies)
+}
+
+func TestSMS_Gateway_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	service := NewSMSUsecase(NewHTTPGateway(getGatewayConfig(server.URL)), "1")
+	err := service.SendNotification(entity.User{PhoneNumber: "78958745"}, "test function")
+	assert.ErrorIs(t, err, ErrGatewayUnavailable)
+
+	err = service.SendNotification(entity.User{PhoneNumber: "123"}, "test function")
+	assert.ErrorIs(t, err, ErrInvalidNumber)
+}
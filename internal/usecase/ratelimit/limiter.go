@@ -0,0 +1,191 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit allows Count events every Per, in bursts of up to Count. The zero
+// Limit doesn't limit anything.
+type Limit struct {
+	Count int
+	Per   time.Duration
+}
+
+// ParseLimit reads a limit written as "count/duration", such as "5/1h".
+func ParseLimit(value string) (Limit, error) {
+	count, per, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, use count/duration such as 5/1h", value)
+	}
+
+	var limit Limit
+	var err error
+	if limit.Count, err = strconv.Atoi(count); err != nil || limit.Count < 1 {
+		return Limit{}, fmt.Errorf("invalid rate limit count %q", count)
+	}
+
+	if limit.Per, err = time.ParseDuration(per); err != nil || limit.Per <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit duration %q", per)
+	}
+
+	return limit, nil
+}
+
+func (l Limit) IsZero() bool {
+	return l.Count <= 0 || l.Per <= 0
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Count)
+}
+
+// Request asks for Tokens tokens, one when zero, from the bucket Key,
+// created full with Limit on first use. A request for more tokens than the
+// bucket holds takes a full bucket.
+type Request struct {
+	Key    string
+	Limit  Limit
+	Tokens int
+}
+
+func (r Request) tokens() float64 {
+	tokens := r.Tokens
+	if tokens < 1 {
+		tokens = 1
+	}
+	if tokens > r.Limit.Count {
+		tokens = r.Limit.Count
+	}
+	return float64(tokens)
+}
+
+// sweepInterval is how often Take removes the buckets that have refilled.
+const sweepInterval = time.Minute
+
+// Limiter keeps token buckets by key in memory, so every bucket starts full
+// again after a restart. A bucket that has refilled is removed, since a new
+// one would start full too.
+type Limiter struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// bucket holds the tokens left at updated; it is full again at full.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Take removes the requested tokens from the bucket of every request, or
+// from none of them when any is short, in which case it returns how long it
+// takes until all of them have enough again. Requests with a zero Limit are
+// ignored.
+func (l *Limiter) Take(now time.Time, requests ...Request) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.sweep(now)
+
+	var wait time.Duration
+	for _, request := range requests {
+		if request.Limit.IsZero() {
+			continue
+		}
+
+		bucket := l.refill(request, now)
+		if bucket.tokens < request.tokens() {
+			missing := time.Duration((request.tokens() - bucket.tokens) * float64(request.Limit.interval()))
+			if missing > wait {
+				wait = missing
+			}
+		}
+	}
+
+	if wait > 0 {
+		return false, wait
+	}
+
+	for _, request := range requests {
+		if !request.Limit.IsZero() {
+			l.buckets[request.Key].add(request, -request.tokens())
+		}
+	}
+
+	return true, 0
+}
+
+// Refund puts the tokens of requests granted by Take back into their
+// buckets, up to their capacity.
+func (l *Limiter) Refund(now time.Time, requests ...Request) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, request := range requests {
+		if request.Limit.IsZero() {
+			continue
+		}
+
+		if _, ok := l.buckets[request.Key]; ok {
+			l.refill(request, now).add(request, request.tokens())
+		}
+	}
+}
+
+// sweep removes the buckets that are full again, at most once every
+// sweepInterval.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < sweepInterval {
+		return
+	}
+	l.swept = now
+
+	for key, bucket := range l.buckets {
+		if !now.Before(bucket.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *Limiter) refill(request Request, now time.Time) *bucket {
+	capacity := float64(request.Limit.Count)
+
+	found, ok := l.buckets[request.Key]
+	if !ok {
+		found = &bucket{tokens: capacity, updated: now}
+		l.buckets[request.Key] = found
+	}
+
+	if elapsed := now.Sub(found.updated); elapsed > 0 {
+		found.tokens += float64(elapsed) / float64(request.Limit.interval())
+		if found.tokens > capacity {
+			found.tokens = capacity
+		}
+		found.updated = now
+	}
+
+	return found
+}
+
+// add changes the tokens, up to the capacity, and when the bucket is full
+// again.
+func (b *bucket) add(request Request, tokens float64) {
+	capacity := float64(request.Limit.Count)
+	b.tokens += tokens
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.full = b.updated.Add(time.Duration((capacity - b.tokens) * float64(request.Limit.interval())))
+}
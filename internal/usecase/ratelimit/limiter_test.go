@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Take_Success(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	user := Request{Key: "1/SMS", Limit: Limit{Count: 2, Per: time.Hour}}
+
+	for i := 0; i < 2; i++ {
+		ok, _ := limiter.Take(now, user)
+		assert.True(t, ok)
+	}
+
+	ok, wait := limiter.Take(now, user)
+	assert.False(t, ok)
+	assert.Equal(t, 30*time.Minute, wait)
+
+	ok, _ = limiter.Take(now.Add(30*time.Minute), user)
+	assert.True(t, ok)
+
+	ok, _ = limiter.Take(now, Request{Key: "2/SMS", Limit: user.Limit}, Request{Key: "unlimited"})
+	assert.True(t, ok)
+}
+
+func TestLimiter_Take_All_Error(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	provider := Request{Key: "SMS", Limit: Limit{Count: 1, Per: time.Second}}
+	user := Request{Key: "1/SMS", Limit: Limit{Count: 5, Per: time.Hour}}
+
+	ok, _ := limiter.Take(now, user, provider)
+	assert.True(t, ok)
+
+	ok, wait := limiter.Take(now, Request{Key: "2/SMS", Limit: user.Limit}, provider)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	// The user's bucket isn't charged when the provider is over its limit.
+	for i := 0; i < 5; i++ {
+		ok, _ = limiter.Take(now.Add(time.Duration(i+1)*time.Second), Request{Key: "2/SMS", Limit: user.Limit}, provider)
+		assert.True(t, ok)
+	}
+}
+
+func TestLimiter_Take_Tokens_Success(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	provider := Request{Key: "SMS", Limit: Limit{Count: 4, Per: 4 * time.Second}, Tokens: 3}
+
+	ok, _ := limiter.Take(now, provider)
+	assert.True(t, ok)
+
+	ok, wait := limiter.Take(now, provider)
+	assert.False(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	// A request larger than the bucket takes it whole instead of waiting
+	// forever.
+	provider.Tokens = 10
+	ok, _ = limiter.Take(now.Add(4*time.Second), provider)
+	assert.True(t, ok)
+}
+
+func TestParseLimit_Success(t *testing.T) {
+	limit, err := ParseLimit("5/1h")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Count: 5, Per: time.Hour}, limit)
+
+	for _, value := range []string{"5", "0/1h", "five/1h", "5/hour", "5/-1h"} {
+		_, err := ParseLimit(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLimiter_Refund_Success(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	user := Request{Key: "1/SMS", Limit: Limit{Count: 1, Per: time.Hour}}
+
+	ok, _ := limiter.Take(now, user)
+	assert.True(t, ok)
+	limiter.Refund(now, user)
+	limiter.Refund(now, user)
+
+	ok, _ = limiter.Take(now, user)
+	assert.True(t, ok)
+	ok, _ = limiter.Take(now, user)
+	assert.False(t, ok)
+}
+
+func TestLimiter_Sweep_Success(t *testing.T) {
+	limiter := NewLimiter()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	limit := Limit{Count: 2, Per: time.Hour}
+
+	limiter.Take(now, Request{Key: "1/SMS", Limit: limit})
+	limiter.Take(now, Request{Key: "2/SMS", Limit: limit}, Request{Key: "2/SMS", Limit: limit})
+	assert.Equal(t, 2, len(limiter.buckets))
+
+	// 1/SMS has refilled after half an hour, while 2/SMS needs an hour.
+	limiter.Take(now.Add(30*time.Minute), Request{Key: "3/SMS", Limit: limit})
+	assert.Equal(t, 2, len(limiter.buckets))
+	assert.NotContains(t, limiter.buckets, "1/SMS")
+
+	limiter.Take(now.Add(time.Hour), Request{Key: "3/SMS", Limit: limit})
+	assert.Equal(t, 1, len(limiter.buckets))
+}
@@ -0,0 +1,76 @@
+package retention
+
+import (
+	"fmt"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"sync"
+	"time"
+)
+
+type RetentionUseCase struct {
+	LogRepository log.Log
+	MaxAge        time.Duration
+	Interval      time.Duration
+
+	now  func() time.Time
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func NewRetentionUseCase(log log.Log, maxAge time.Duration, interval time.Duration) *RetentionUseCase {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &RetentionUseCase{
+		LogRepository: log,
+		MaxAge:        maxAge,
+		Interval:      interval,
+		now:           time.Now,
+	}
+}
+
+// Prune deletes the logs older than MaxAge and returns how many were removed.
+func (r *RetentionUseCase) Prune() (int, error) {
+	cutoff := r.now().Add(-r.MaxAge)
+	return r.LogRepository.DeleteLogs(entity.LogFilter{To: cutoff})
+}
+
+// Start prunes once right away and then every Interval until Stop.
+func (r *RetentionUseCase) Start() {
+	r.stop = make(chan struct{})
+	r.done.Add(1)
+
+	go func() {
+		defer r.done.Done()
+
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		for {
+			r.prune()
+
+			select {
+			case <-ticker.C:
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (r *RetentionUseCase) Stop() {
+	close(r.stop)
+	r.done.Wait()
+}
+
+func (r *RetentionUseCase) prune() {
+	deleted, err := r.Prune()
+	if err != nil {
+		fmt.Printf("Failed to prune logs: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Pruned %d logs older than %v\n", deleted, r.MaxAge)
+}
@@ -0,0 +1,63 @@
+package retention
+
+import (
+	"errors"
+	"notification/internal/entity"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var anyError = errors.New("Error")
+
+func TestPrune_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	service := NewRetentionUseCase(logEntity, 90*24*time.Hour, time.Hour)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	logEntity.EXPECT().DeleteLogs(entity.LogFilter{To: now.Add(-90 * 24 * time.Hour)}).Return(12, nil)
+
+	deleted, err := service.Prune()
+	assert.NoError(t, err)
+	assert.Equal(t, 12, deleted)
+}
+
+func TestPrune_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	service := NewRetentionUseCase(logEntity, time.Hour, time.Hour)
+
+	logEntity.EXPECT().DeleteLogs(gomock.Any()).Return(0, anyError)
+
+	_, err := service.Prune()
+	assert.ErrorIs(t, err, anyError)
+}
+
+func TestStart_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	logEntity := log.NewMockLog(controller)
+	service := NewRetentionUseCase(logEntity, time.Hour, 10*time.Millisecond)
+
+	pruned := make(chan struct{}, 10)
+	logEntity.EXPECT().DeleteLogs(gomock.Any()).DoAndReturn(func(entity.LogFilter) (int, error) {
+		pruned <- struct{}{}
+		return 0, nil
+	}).MinTimes(2)
+
+	service.Start()
+	<-pruned
+	<-pruned
+	service.Stop()
+}
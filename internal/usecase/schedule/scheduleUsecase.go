@@ -0,0 +1,363 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"notification/internal/usecase/notification"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var ErrScheduleNotActive = errors.New("schedule is no longer active")
+
+type ValidationError struct {
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ScheduleUseCase sends the due schedules every Interval. Completed and
+// cancelled schedules are removed once they are older than Retention, unless
+// it is zero.
+type ScheduleUseCase struct {
+	ScheduleRepository  log.Schedule
+	NotificationUseCase *notification.NotificationUseCase
+	Interval            time.Duration
+	Retention           time.Duration
+
+	now   func() time.Time
+	mutex sync.Mutex
+	stop  chan struct{}
+	done  sync.WaitGroup
+}
+
+func NewScheduleUseCase(schedule log.Schedule, notification *notification.NotificationUseCase, interval time.Duration) *ScheduleUseCase {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return &ScheduleUseCase{
+		ScheduleRepository:  schedule,
+		NotificationUseCase: notification,
+		Interval:            interval,
+		now:                 time.Now,
+	}
+}
+
+// CreateSchedule stores a notification to be sent at SendAt, or on every
+// occurrence of Cron.
+func (s *ScheduleUseCase) CreateSchedule(schedule entity.Schedule) (entity.Schedule, error) {
+	now := s.now()
+	nextRun, err := s.validate(schedule, now)
+	if err != nil {
+		return schedule, err
+	}
+
+	schedule.ID = entity.NewID()
+	schedule.Status = entity.ScheduleActive
+	schedule.NextRun = nextRun
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return schedule, s.ScheduleRepository.SaveSchedule(schedule)
+}
+
+// UpdateSchedule replaces the notification and timing of a schedule that is
+// still active. Its run history is kept.
+func (s *ScheduleUseCase) UpdateSchedule(schedule entity.Schedule) (entity.Schedule, error) {
+	now := s.now()
+	nextRun, err := s.validate(schedule, now)
+	if err != nil {
+		return schedule, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing, err := s.getSchedule(schedule.ID)
+	if err != nil {
+		return schedule, err
+	}
+
+	if existing.Status != entity.ScheduleActive {
+		return existing, ErrScheduleNotActive
+	}
+
+	existing.Notification = schedule.Notification
+	existing.SendAt = schedule.SendAt
+	existing.Cron = schedule.Cron
+	existing.NextRun = nextRun
+	existing.UpdatedAt = now
+
+	return existing, s.ScheduleRepository.SaveSchedule(existing)
+}
+
+func (s *ScheduleUseCase) CancelSchedule(id string) (entity.Schedule, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedule, err := s.getSchedule(id)
+	if err != nil {
+		return schedule, err
+	}
+
+	if schedule.Status != entity.ScheduleActive {
+		return schedule, ErrScheduleNotActive
+	}
+
+	schedule.Status = entity.ScheduleCancelled
+	schedule.UpdatedAt = s.now()
+
+	return schedule, s.ScheduleRepository.SaveSchedule(schedule)
+}
+
+func (s *ScheduleUseCase) GetSchedule(id string) (entity.Schedule, error) {
+	return s.getSchedule(id)
+}
+
+// GetSchedules returns the schedules created through the API. Deliveries
+// deferred by quiet hours or rate limits are sent by the scheduler too, but
+// aren't listed.
+func (s *ScheduleUseCase) GetSchedules() ([]entity.Schedule, error) {
+	schedules, err := s.ScheduleRepository.GetSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	listed := []entity.Schedule{}
+	for _, schedule := range schedules {
+		if !schedule.IsDeferredDelivery() {
+			listed = append(listed, schedule)
+		}
+	}
+
+	return listed, nil
+}
+
+// getSchedule returns a schedule created through the API; a deferred
+// delivery is not found.
+func (s *ScheduleUseCase) getSchedule(id string) (entity.Schedule, error) {
+	schedule, err := s.ScheduleRepository.GetSchedule(id)
+	if err == nil && schedule.IsDeferredDelivery() {
+		return entity.Schedule{}, log.ErrScheduleNotFound
+	}
+
+	return schedule, err
+}
+
+// RunDue sends every schedule whose time has come and returns how many were
+// sent. The due schedules are moved to their next run with a single write
+// before they are sent, so a crash while sending skips that run instead of
+// repeating it. Runs missed while the service was down are sent once. Every
+// claimed schedule is sent even when another one fails, and the first error
+// is returned afterwards.
+func (s *ScheduleUseCase) RunDue() (int, error) {
+	due, err := s.claimDue()
+
+	for i := range due {
+		due[i].Error = ""
+		if sendErr := s.send(due[i]); sendErr != nil {
+			due[i].Error = sendErr.Error()
+		}
+	}
+
+	if recordErr := s.recordRuns(due); err == nil {
+		err = recordErr
+	}
+
+	return len(due), err
+}
+
+// Start checks for due schedules every Interval until Stop.
+func (s *ScheduleUseCase) Start() {
+	s.stop = make(chan struct{})
+	s.done.Add(1)
+
+	go func() {
+		defer s.done.Done()
+
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.RunDue(); err != nil {
+				fmt.Printf("Failed to run schedules: %v\n", err)
+			}
+			s.prune()
+
+			select {
+			case <-ticker.C:
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *ScheduleUseCase) Stop() {
+	close(s.stop)
+	s.done.Wait()
+}
+
+// claimDue moves the due schedules to their next run and saves them with a
+// single write. A schedule whose cron can't be evaluated is left out and
+// reported, without holding back the others.
+func (s *ScheduleUseCase) claimDue() ([]entity.Schedule, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedules, err := s.ScheduleRepository.GetSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	var due []entity.Schedule
+	var cronErr error
+	for _, schedule := range schedules {
+		if !schedule.IsDue(now) {
+			continue
+		}
+
+		if schedule.IsRecurring() {
+			next, err := nextRun(schedule.Cron, now)
+			if err != nil {
+				if cronErr == nil {
+					cronErr = fmt.Errorf("schedule %s: %v", schedule.ID, err)
+				}
+				continue
+			}
+			schedule.NextRun = next
+		} else {
+			schedule.Status = entity.ScheduleCompleted
+		}
+		schedule.LastRun = now
+		schedule.Runs++
+		schedule.UpdatedAt = now
+
+		due = append(due, schedule)
+	}
+
+	if err := s.ScheduleRepository.SaveSchedules(due); err != nil {
+		return nil, err
+	}
+
+	return due, cronErr
+}
+
+// send delivers a schedule to every subscriber, or only to its user and
+// channel when it has one.
+func (s *ScheduleUseCase) send(schedule entity.Schedule) error {
+	if schedule.UserID != 0 {
+		_, err := s.NotificationUseCase.SendToUser(schedule.Notification, schedule.UserID, schedule.Channel)
+		return err
+	}
+
+	_, err := s.NotificationUseCase.SendNotification(schedule.Notification)
+	return err
+}
+
+// recordRuns saves the error of each run with a single write. The schedules
+// are read again, so that changes made while they were sent are kept.
+func (s *ScheduleUseCase) recordRuns(runs []entity.Schedule) error {
+	if len(runs) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	schedules, err := s.ScheduleRepository.GetSchedules()
+	if err != nil {
+		return err
+	}
+
+	errs := map[string]string{}
+	for _, run := range runs {
+		errs[run.ID] = run.Error
+	}
+
+	now := s.now()
+	var recorded []entity.Schedule
+	for _, schedule := range schedules {
+		if runErr, ok := errs[schedule.ID]; ok {
+			schedule.Error = runErr
+			schedule.UpdatedAt = now
+			recorded = append(recorded, schedule)
+		}
+	}
+
+	return s.ScheduleRepository.SaveSchedules(recorded)
+}
+
+// prune removes the finished schedules older than the retention period.
+func (s *ScheduleUseCase) prune() {
+	if s.Retention <= 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.ScheduleRepository.DeleteFinishedSchedules(s.now().Add(-s.Retention)); err != nil {
+		fmt.Printf("Failed to prune schedules: %v\n", err)
+	}
+}
+
+// validate checks the schedule and returns the time of its first run.
+func (s *ScheduleUseCase) validate(schedule entity.Schedule, now time.Time) (time.Time, error) {
+	notification := schedule.Notification
+	if !notification.Category.IsValid() {
+		return time.Time{}, ValidationError{Message: fmt.Sprintf("invalid category: %s", notification.Category)}
+	}
+
+	if !notification.HasContent() {
+		return time.Time{}, ValidationError{Message: "message, messages or template_id is required"}
+	}
+
+	if !notification.Priority.IsValid() {
+		return time.Time{}, ValidationError{Message: fmt.Sprintf("invalid priority: %s", notification.Priority)}
+	}
+
+	if err := s.NotificationUseCase.CheckNotification(notification); err != nil {
+		if errors.Is(err, log.ErrTemplateNotFound) {
+			return time.Time{}, ValidationError{Message: fmt.Sprintf("template not found: %s", notification.TemplateID)}
+		}
+		return time.Time{}, err
+	}
+
+	switch {
+	case schedule.SendAt.IsZero() == (schedule.Cron == ""):
+		return time.Time{}, ValidationError{Message: "either send_at or cron is required"}
+	case schedule.Cron != "":
+		next, err := nextRun(schedule.Cron, now)
+		if err != nil {
+			return time.Time{}, ValidationError{Message: fmt.Sprintf("invalid cron: %v", err)}
+		}
+		return next, nil
+	case !schedule.SendAt.After(now):
+		return time.Time{}, ValidationError{Message: "send_at must be in the future"}
+	default:
+		return schedule.SendAt, nil
+	}
+}
+
+// nextRun accepts standard five-field cron expressions and descriptors such
+// as @daily, evaluated in UTC unless prefixed with CRON_TZ=.
+func nextRun(expression string, after time.Time) (time.Time, error) {
+	parsed, err := cron.ParseStandard(expression)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parsed.Next(after.UTC()), nil
+}
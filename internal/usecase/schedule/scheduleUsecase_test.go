@@ -0,0 +1,294 @@
+package schedule
+
+import (
+	"errors"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	"notification/internal/usecase/notification"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	anyError = errors.New("Error")
+	now      = time.Date(2024, 6, 3, 10, 30, 0, 0, time.UTC)
+)
+
+func TestCreateSchedule_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	service := newService(t, controller, nil, nil)
+
+	created, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), Cron: "0 8 * * *"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, entity.ScheduleActive, created.Status)
+	assert.Equal(t, time.Date(2024, 6, 4, 8, 0, 0, 0, time.UTC), created.NextRun)
+
+	sendAt := now.Add(time.Hour)
+	created, err = service.CreateSchedule(entity.Schedule{Notification: getNotification(), SendAt: sendAt})
+	assert.NoError(t, err)
+	assert.Equal(t, sendAt, created.NextRun)
+
+	schedules, err := service.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(schedules))
+}
+
+func TestCreateSchedule_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	service := newService(t, controller, nil, nil)
+
+	invalid := []entity.Schedule{
+		{Notification: getNotification()},
+		{Notification: getNotification(), Cron: "0 8 * * *", SendAt: now.Add(time.Hour)},
+		{Notification: getNotification(), Cron: "every morning"},
+		{Notification: getNotification(), SendAt: now.Add(-time.Minute)},
+		{Notification: entity.Notification{Category: entity.FinanceCategory}, Cron: "@daily"},
+		{Notification: entity.Notification{Message: "Market summary", Category: "Cooking"}, Cron: "@daily"},
+	}
+
+	for _, schedule := range invalid {
+		_, err := service.CreateSchedule(schedule)
+		assert.ErrorAs(t, err, &ValidationError{}, schedule)
+	}
+}
+
+func TestRunDue_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	logEntity := log.NewMockLog(controller)
+	service := newService(t, controller, userEntity, logEntity)
+
+	once, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), SendAt: now.Add(time.Minute)})
+	assert.NoError(t, err)
+	daily, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), Cron: "@daily"})
+	assert.NoError(t, err)
+	later, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), SendAt: now.Add(48 * time.Hour)})
+	assert.NoError(t, err)
+
+	userEntity.EXPECT().GetUsersByCategory(entity.FinanceCategory).Return([]entity.User{getUser(1)}, nil)
+	userEntity.EXPECT().GetUsersByCategory(entity.FinanceCategory).Return(nil, anyError)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil)
+
+	service.now = func() time.Time { return now.Add(24 * time.Hour) }
+	sent, err := service.RunDue()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	once, _ = service.GetSchedule(once.ID)
+	assert.Equal(t, entity.ScheduleCompleted, once.Status)
+	assert.Equal(t, 1, once.Runs)
+
+	daily, _ = service.GetSchedule(daily.ID)
+	assert.Equal(t, entity.ScheduleActive, daily.Status)
+	assert.Equal(t, time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC), daily.NextRun)
+	assert.Equal(t, "Error", daily.Error)
+
+	later, _ = service.GetSchedule(later.ID)
+	assert.Equal(t, 0, later.Runs)
+
+	sent, err = service.RunDue()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+func TestRunDue_User_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	logEntity := log.NewMockLog(controller)
+	service := newService(t, controller, userEntity, logEntity)
+
+	deferred := entity.Schedule{
+		ID:           "deferred",
+		Notification: getNotification(),
+		UserID:       1,
+		Channel:      entity.EmailChannel,
+		SendAt:       now,
+		Status:       entity.ScheduleActive,
+		NextRun:      now,
+	}
+	assert.NoError(t, service.ScheduleRepository.SaveSchedule(deferred))
+
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil)
+
+	sent, err := service.RunDue()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	deferred, _ = service.ScheduleRepository.GetSchedule("deferred")
+	assert.Equal(t, entity.ScheduleCompleted, deferred.Status)
+	assert.Empty(t, deferred.Error)
+}
+
+func TestDeferredDelivery_Hidden_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	service := newService(t, controller, nil, nil)
+
+	deferred := entity.Schedule{
+		ID:           "deferred",
+		Notification: getNotification(),
+		UserID:       1,
+		Channel:      entity.SMSChannel,
+		SendAt:       now.Add(time.Hour),
+		Status:       entity.ScheduleActive,
+		NextRun:      now.Add(time.Hour),
+	}
+	assert.NoError(t, service.ScheduleRepository.SaveSchedule(deferred))
+	created, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), Cron: "@daily"})
+	assert.NoError(t, err)
+
+	schedules, err := service.GetSchedules()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(schedules))
+	assert.Equal(t, created.ID, schedules[0].ID)
+
+	_, err = service.GetSchedule("deferred")
+	assert.ErrorIs(t, err, repositories.ErrScheduleNotFound)
+
+	_, err = service.UpdateSchedule(entity.Schedule{ID: "deferred", Notification: getNotification(), Cron: "@daily"})
+	assert.ErrorIs(t, err, repositories.ErrScheduleNotFound)
+
+	_, err = service.CancelSchedule("deferred")
+	assert.ErrorIs(t, err, repositories.ErrScheduleNotFound)
+
+	deferred, err = service.ScheduleRepository.GetSchedule("deferred")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ScheduleActive, deferred.Status)
+}
+
+func TestRunDue_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	logEntity := log.NewMockLog(controller)
+	service := newService(t, controller, userEntity, logEntity)
+
+	broken := entity.Schedule{ID: "broken", Notification: getNotification(), Cron: "every morning", Status: entity.ScheduleActive, NextRun: now}
+	once := entity.Schedule{ID: "once", Notification: getNotification(), SendAt: now, Status: entity.ScheduleActive, NextRun: now}
+	assert.NoError(t, service.ScheduleRepository.SaveSchedules([]entity.Schedule{broken, once}))
+
+	userEntity.EXPECT().GetUsersByCategory(entity.FinanceCategory).Return([]entity.User{getUser(1)}, nil)
+	logEntity.EXPECT().SaveLog(gomock.Any()).Return(nil)
+
+	sent, err := service.RunDue()
+	assert.ErrorContains(t, err, "schedule broken")
+	assert.Equal(t, 1, sent)
+
+	once, _ = service.GetSchedule("once")
+	assert.Equal(t, entity.ScheduleCompleted, once.Status)
+
+	broken, _ = service.GetSchedule("broken")
+	assert.Equal(t, 0, broken.Runs)
+
+	scheduleEntity := log.NewMockSchedule(controller)
+	service.ScheduleRepository = scheduleEntity
+	scheduleEntity.EXPECT().GetSchedules().Return([]entity.Schedule{{ID: "due", Notification: getNotification(), SendAt: now, Status: entity.ScheduleActive, NextRun: now}}, nil)
+	scheduleEntity.EXPECT().SaveSchedules(gomock.Any()).Return(anyError)
+
+	sent, err = service.RunDue()
+	assert.ErrorIs(t, err, anyError)
+	assert.Equal(t, 0, sent)
+}
+
+func TestPrune_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	service := newService(t, controller, nil, nil)
+	service.Retention = 24 * time.Hour
+
+	created, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), Cron: "@daily"})
+	assert.NoError(t, err)
+	cancelled, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), Cron: "@daily"})
+	assert.NoError(t, err)
+	_, err = service.CancelSchedule(cancelled.ID)
+	assert.NoError(t, err)
+
+	service.prune()
+	schedules, _ := service.GetSchedules()
+	assert.Equal(t, 2, len(schedules))
+
+	service.now = func() time.Time { return now.Add(48 * time.Hour) }
+	service.prune()
+	schedules, _ = service.GetSchedules()
+	assert.Equal(t, 1, len(schedules))
+	assert.Equal(t, created.ID, schedules[0].ID)
+}
+
+func TestUpdateAndCancelSchedule_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	service := newService(t, controller, nil, nil)
+
+	created, err := service.CreateSchedule(entity.Schedule{Notification: getNotification(), Cron: "@daily"})
+	assert.NoError(t, err)
+
+	updated := entity.Schedule{ID: created.ID, Notification: getNotification(), Cron: "0 18 * * 1-5"}
+	updated.Notification.Message = "Closing summary"
+	updated, err = service.UpdateSchedule(updated)
+	assert.NoError(t, err)
+	assert.Equal(t, "Closing summary", updated.Notification.Message)
+	assert.Equal(t, time.Date(2024, 6, 3, 18, 0, 0, 0, time.UTC), updated.NextRun)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+
+	cancelled, err := service.CancelSchedule(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ScheduleCancelled, cancelled.Status)
+
+	_, err = service.CancelSchedule(created.ID)
+	assert.ErrorIs(t, err, ErrScheduleNotActive)
+
+	_, err = service.UpdateSchedule(updated)
+	assert.ErrorIs(t, err, ErrScheduleNotActive)
+
+	_, err = service.CancelSchedule("unknown")
+	assert.ErrorIs(t, err, repositories.ErrScheduleNotFound)
+}
+
+func newService(t *testing.T, controller *gomock.Controller, userEntity *log.MockUser, logEntity *log.MockLog) *ScheduleUseCase {
+	if userEntity == nil {
+		userEntity = log.NewMockUser(controller)
+	}
+	if logEntity == nil {
+		logEntity = log.NewMockLog(controller)
+	}
+
+	notificationUseCase := notification.NewNotificationUseCase(logEntity, userEntity, log.NewMockDeadLetter(controller))
+	service := NewScheduleUseCase(repositories.NewScheduleRepository(t.TempDir()+"/schedules.json"), notificationUseCase, time.Second)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func getUser(id int) entity.User {
+	return entity.User{
+		ID:         id,
+		Name:       "Antony Smith",
+		Email:      "antony.smith@gmail.com",
+		Subscribed: []entity.Category{entity.FinanceCategory},
+		Channels:   []entity.Channel{entity.EmailChannel},
+	}
+}
+
+func getNotification() entity.Notification {
+	return entity.Notification{
+		Message:  "Market summary",
+		Category: entity.FinanceCategory,
+	}
+}
@@ -0,0 +1,238 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"notification/internal/entity"
+
+	html "html/template"
+	text "text/template"
+)
+
+// Data is what a template can use: the recipient, the notification category
+// and message, and the data sent with the notification, e.g.
+// "Hi {{.User.Name}}, {{.Data.team}} scored".
+type Data struct {
+	User     entity.User
+	Category entity.Category
+	Message  string
+	Data     map[string]interface{}
+}
+
+// Compiled is a template parsed once, in each of its locales, to be
+// rendered for many recipients.
+type Compiled struct {
+	template entity.Template
+	locales  map[string]parsedBodies
+}
+
+// parsedBodies holds the bodies of one locale, with the channels without
+// their own body falling back to Body. An empty body is nil.
+type parsedBodies struct {
+	body         *text.Template
+	sms          *text.Template
+	emailSubject *text.Template
+	emailBody    *text.Template
+	emailHTML    *html.Template
+	pushTitle    *text.Template
+	pushBody     *text.Template
+}
+
+// Compile parses the bodies of the template and its translations. Data keys
+// missing from the notification are an error when rendering instead of
+// rendering "<no value>".
+func Compile(template entity.Template) (*Compiled, error) {
+	compiled := &Compiled{template: template, locales: map[string]parsedBodies{}}
+
+	base := template.Locale
+	if base == "" {
+		base = entity.DefaultLocale
+	}
+
+	locales := []string{base}
+	for locale := range template.Translations {
+		locales = append(locales, locale)
+	}
+
+	for _, locale := range locales {
+		bodies, _ := template.Localize(locale)
+		parsed, err := parseBodies(bodies)
+		if err != nil {
+			return nil, err
+		}
+		compiled.locales[locale] = parsed
+	}
+
+	return compiled, nil
+}
+
+func parseBodies(bodies entity.TemplateTranslation) (parsedBodies, error) {
+	var parsed parsedBodies
+	texts := []struct {
+		target **text.Template
+		name   string
+		body   string
+	}{
+		{&parsed.body, "body", bodies.Body},
+		{&parsed.sms, "sms", firstOf(bodies.SMS.Body, bodies.Body)},
+		{&parsed.emailSubject, "subject", bodies.Email.Subject},
+		{&parsed.emailBody, "body", firstOf(bodies.Email.Body, bodies.Body)},
+		{&parsed.pushTitle, "title", bodies.Push.Title},
+		{&parsed.pushBody, "push", firstOf(bodies.Push.Body, bodies.Body)},
+	}
+
+	for _, body := range texts {
+		if body.body == "" {
+			continue
+		}
+
+		parsedText, err := text.New(body.name).Option("missingkey=error").Parse(body.body)
+		if err != nil {
+			return parsed, err
+		}
+		*body.target = parsedText
+	}
+
+	if bodies.Email.HTML != "" {
+		parsedHTML, err := html.New("html").Option("missingkey=error").Parse(bodies.Email.HTML)
+		if err != nil {
+			return parsed, err
+		}
+		parsed.emailHTML = parsedHTML
+	}
+
+	return parsed, nil
+}
+
+// Render builds the content of the template for one channel, in the
+// recipient's locale when the template has it. It parses the template for
+// this one content; use Compile to render it for many recipients.
+func Render(template entity.Template, channel entity.Channel, data Data) (entity.Content, error) {
+	compiled, err := Compile(template)
+	if err != nil {
+		return entity.Content{}, err
+	}
+
+	return compiled.Render(channel, data)
+}
+
+// Render builds the content for one channel, in the recipient's locale when
+// the template has it. A channel without its own body uses the template's
+// Body.
+func (c *Compiled) Render(channel entity.Channel, data Data) (entity.Content, error) {
+	_, locale := c.template.Localize(data.User.Locale)
+	bodies := c.locales[locale]
+	content := entity.Content{Locale: locale}
+
+	var err error
+	switch channel {
+	case entity.SMSChannel:
+		content.Body, err = executeText(bodies.sms, data)
+	case entity.EmailChannel:
+		if content.Subject, err = executeText(bodies.emailSubject, data); err != nil {
+			return content, err
+		}
+		if content.Body, err = executeText(bodies.emailBody, data); err != nil {
+			return content, err
+		}
+		content.HTML, err = executeHTML(bodies.emailHTML, data)
+	case entity.PushChannel:
+		if content.Title, err = executeText(bodies.pushTitle, data); err != nil {
+			return content, err
+		}
+		content.Body, err = executeText(bodies.pushBody, data)
+	default:
+		content.Body, err = executeText(bodies.body, data)
+	}
+
+	return content, err
+}
+
+// Validate parses every body of the template and its translations so mistakes
+// are reported when the template is saved rather than when it is sent. Every
+// translation needs its own Body; its other empty fields are taken from the
+// template.
+func Validate(template entity.Template) error {
+	if template.Locale != "" && !entity.IsValidLocale(template.Locale) {
+		return ValidationError{Message: fmt.Sprintf("invalid locale: %s", template.Locale)}
+	}
+
+	bodies, _ := template.Localize(template.Locale)
+	if err := validateBodies("", bodies); err != nil {
+		return err
+	}
+
+	for locale, translation := range template.Translations {
+		if !entity.IsValidLocale(locale) {
+			return ValidationError{Message: fmt.Sprintf("invalid locale: %s", locale)}
+		}
+
+		if translation.Body == "" {
+			return ValidationError{Message: fmt.Sprintf("%s translation needs a body", locale)}
+		}
+
+		if err := validateBodies(locale+" ", translation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateBodies(prefix string, bodies entity.TemplateTranslation) error {
+	texts := map[string]string{
+		"body":          bodies.Body,
+		"sms body":      bodies.SMS.Body,
+		"email subject": bodies.Email.Subject,
+		"email body":    bodies.Email.Body,
+		"push title":    bodies.Push.Title,
+		"push body":     bodies.Push.Body,
+	}
+
+	for name, body := range texts {
+		if _, err := text.New(name).Parse(body); err != nil {
+			return ValidationError{Message: fmt.Sprintf("invalid %s%s: %v", prefix, name, err)}
+		}
+	}
+
+	if _, err := html.New("email html").Parse(bodies.Email.HTML); err != nil {
+		return ValidationError{Message: fmt.Sprintf("invalid %semail html: %v", prefix, err)}
+	}
+
+	return nil
+}
+
+func executeText(parsed *text.Template, data Data) (string, error) {
+	if parsed == nil {
+		return "", nil
+	}
+
+	var rendered bytes.Buffer
+	if err := parsed.Execute(&rendered, data); err != nil {
+		return "", err
+	}
+
+	return rendered.String(), nil
+}
+
+func executeHTML(parsed *html.Template, data Data) (string, error) {
+	if parsed == nil {
+		return "", nil
+	}
+
+	var rendered bytes.Buffer
+	if err := parsed.Execute(&rendered, data); err != nil {
+		return "", err
+	}
+
+	return rendered.String(), nil
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package template
+
+import (
+	"notification/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender_Success(t *testing.T) {
+	template := getTemplate("goal")
+	data := Data{
+		User:     entity.User{Name: "Mary <Alexander>"},
+		Category: entity.SportsCategory,
+		Message:  "Final score",
+		Data:     map[string]interface{}{"team": "Lions"},
+	}
+
+	content, err := Render(template, entity.SMSChannel, data)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Content{Body: "Lions scored, Mary <Alexander>", Locale: entity.DefaultLocale}, content)
+
+	content, err = Render(template, entity.EmailChannel, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sports: Final score", content.Subject)
+	assert.Equal(t, "Hi Mary <Alexander>, Lions scored", content.Body)
+	assert.Equal(t, "<p>Hi Mary &lt;Alexander&gt;</p>", content.HTML)
+
+	content, err = Render(template, entity.PushChannel, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Lions", content.Title)
+	assert.Equal(t, "Hi Mary <Alexander>, Lions scored", content.Body)
+}
+
+func TestRender_Locale_Success(t *testing.T) {
+	template := getTemplate("goal")
+	template.Translations = map[string]entity.TemplateTranslation{
+		"pt-BR": {Body: "Oi {{.User.Name}}, gol do {{.Data.team}}"},
+	}
+	data := Data{Data: map[string]interface{}{"team": "Leões"}}
+
+	data.User.Locale = "pt-br"
+	content, err := Render(template, entity.PushChannel, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "pt-BR", content.Locale)
+	assert.Equal(t, "Oi , gol do Leões", content.Body)
+	assert.Equal(t, "Leões", content.Title)
+
+	content, err = Render(template, entity.EmailChannel, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Oi , gol do Leões", content.Body)
+	assert.Equal(t, "<p>Hi </p>", content.HTML)
+
+	data.User.Locale = "pt-PT"
+	content, err = Render(template, entity.PushChannel, data)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.DefaultLocale, content.Locale)
+	assert.Equal(t, "Leões", content.Title)
+}
+
+func TestRender_Error(t *testing.T) {
+	_, err := Render(getTemplate("goal"), entity.SMSChannel, Data{Data: map[string]interface{}{}})
+	assert.Error(t, err)
+}
+
+func TestValidate_Error(t *testing.T) {
+	template := getTemplate("goal")
+	template.Push.Title = "{{.Data.team"
+	assert.ErrorAs(t, Validate(template), &ValidationError{})
+
+	template = getTemplate("goal")
+	template.Email.HTML = "<p>{{end}}</p>"
+	assert.ErrorAs(t, Validate(template), &ValidationError{})
+
+	template = getTemplate("goal")
+	template.Translations = map[string]entity.TemplateTranslation{
+		"pt": {Push: entity.PushTemplate{Title: "Gol!"}},
+	}
+	assert.ErrorAs(t, Validate(template), &ValidationError{})
+}
+
+func getTemplate(id string) entity.Template {
+	return entity.Template{
+		ID:   id,
+		Body: "Hi {{.User.Name}}, {{.Data.team}} scored",
+		SMS:  entity.SMSTemplate{Body: "{{.Data.team}} scored, {{.User.Name}}"},
+		Email: entity.EmailTemplate{
+			Subject: "{{.Category}}: {{.Message}}",
+			HTML:    "<p>Hi {{.User.Name}}</p>",
+		},
+		Push: entity.PushTemplate{Title: "{{.Data.team}}"},
+	}
+}
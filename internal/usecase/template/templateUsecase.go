@@ -0,0 +1,85 @@
+package template
+
+import (
+	"errors"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"time"
+)
+
+var ErrTemplateAlreadyExists = errors.New("template already exists")
+
+type ValidationError struct {
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+type TemplateUseCase struct {
+	TemplateRepository log.Template
+}
+
+func NewTemplateUseCase(template log.Template) *TemplateUseCase {
+	return &TemplateUseCase{
+		TemplateRepository: template,
+	}
+}
+
+func (u TemplateUseCase) CreateTemplate(template entity.Template) (entity.Template, error) {
+	if err := validateTemplate(template); err != nil {
+		return template, err
+	}
+
+	_, err := u.TemplateRepository.GetTemplate(template.ID)
+	if err == nil {
+		return template, ErrTemplateAlreadyExists
+	}
+	if !errors.Is(err, log.ErrTemplateNotFound) {
+		return template, err
+	}
+
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = template.CreatedAt
+	return template, u.TemplateRepository.SaveTemplate(template)
+}
+
+func (u TemplateUseCase) UpdateTemplate(template entity.Template) (entity.Template, error) {
+	if err := validateTemplate(template); err != nil {
+		return template, err
+	}
+
+	existing, err := u.TemplateRepository.GetTemplate(template.ID)
+	if err != nil {
+		return template, err
+	}
+
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedAt = time.Now()
+	return template, u.TemplateRepository.SaveTemplate(template)
+}
+
+func (u TemplateUseCase) GetTemplate(id string) (entity.Template, error) {
+	return u.TemplateRepository.GetTemplate(id)
+}
+
+func (u TemplateUseCase) GetTemplates() ([]entity.Template, error) {
+	return u.TemplateRepository.GetTemplates()
+}
+
+func (u TemplateUseCase) DeleteTemplate(id string) error {
+	return u.TemplateRepository.DeleteTemplate(id)
+}
+
+func validateTemplate(template entity.Template) error {
+	if template.ID == "" {
+		return ValidationError{Message: "id is required"}
+	}
+
+	if template.Body == "" && (template.SMS.Body == "" || template.Email.Body == "" || template.Push.Body == "") {
+		return ValidationError{Message: "body is required unless every channel has its own body"}
+	}
+
+	return Validate(template)
+}
@@ -0,0 +1,83 @@
+package template
+
+import (
+	"errors"
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	log "notification/test/platform"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var anyError = errors.New("Error")
+
+func TestCreateTemplate_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	templateEntity := log.NewMockTemplate(controller)
+	service := NewTemplateUseCase(templateEntity)
+
+	templateEntity.EXPECT().GetTemplate("goal").Return(entity.Template{}, repositories.ErrTemplateNotFound)
+	templateEntity.EXPECT().SaveTemplate(gomock.Any()).Return(nil)
+
+	created, err := service.CreateTemplate(getTemplate("goal"))
+	assert.NoError(t, err)
+	assert.False(t, created.CreatedAt.IsZero())
+}
+
+func TestCreateTemplate_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	templateEntity := log.NewMockTemplate(controller)
+	service := NewTemplateUseCase(templateEntity)
+
+	_, err := service.CreateTemplate(entity.Template{Body: "Hi"})
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	_, err = service.CreateTemplate(entity.Template{ID: "empty", SMS: entity.SMSTemplate{Body: "Hi"}})
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	templateEntity.EXPECT().GetTemplate("goal").Return(getTemplate("goal"), nil)
+	_, err = service.CreateTemplate(getTemplate("goal"))
+	assert.ErrorIs(t, err, ErrTemplateAlreadyExists)
+
+	templateEntity.EXPECT().GetTemplate("goal").Return(entity.Template{}, anyError)
+	_, err = service.CreateTemplate(getTemplate("goal"))
+	assert.ErrorIs(t, err, anyError)
+}
+
+func TestUpdateTemplate_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	templateEntity := log.NewMockTemplate(controller)
+	service := NewTemplateUseCase(templateEntity)
+
+	existing := getTemplate("goal")
+	existing.CreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	templateEntity.EXPECT().GetTemplate("goal").Return(existing, nil)
+	templateEntity.EXPECT().SaveTemplate(gomock.Any()).Return(nil)
+
+	updated, err := service.UpdateTemplate(getTemplate("goal"))
+	assert.NoError(t, err)
+	assert.Equal(t, existing.CreatedAt, updated.CreatedAt)
+}
+
+func TestUpdateTemplate_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	templateEntity := log.NewMockTemplate(controller)
+	service := NewTemplateUseCase(templateEntity)
+
+	templateEntity.EXPECT().GetTemplate("goal").Return(entity.Template{}, repositories.ErrTemplateNotFound)
+
+	_, err := service.UpdateTemplate(getTemplate("goal"))
+	assert.ErrorIs(t, err, repositories.ErrTemplateNotFound)
+}
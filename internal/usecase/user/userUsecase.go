@@ -0,0 +1,279 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"notification/internal/entity"
+	log "notification/internal/platform/repositories"
+	"sync"
+	"time"
+)
+
+var (
+	ErrAlreadySubscribed = errors.New("user already subscribed to category")
+	ErrNotSubscribed     = errors.New("user not subscribed to category")
+	ErrChannelEnabled    = errors.New("channel already enabled")
+	ErrChannelDisabled   = errors.New("channel not enabled")
+)
+
+type ValidationError struct {
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+type UserUseCase struct {
+	UserRepository   log.User
+	DeviceRepository log.Device
+
+	// updates serializes the changes that read a user and then save it, so
+	// concurrent changes to the same user don't overwrite each other.
+	updates *sync.Mutex
+}
+
+func NewUserUseCase(user log.User, device log.Device) *UserUseCase {
+	return &UserUseCase{
+		UserRepository:   user,
+		DeviceRepository: device,
+		updates:          &sync.Mutex{},
+	}
+}
+
+func (u UserUseCase) CreateUser(user entity.User) (entity.User, error) {
+	if err := validateUser(user); err != nil {
+		return user, err
+	}
+
+	return u.UserRepository.CreateUser(user)
+}
+
+func (u UserUseCase) UpdateUser(user entity.User) error {
+	if err := validateUser(user); err != nil {
+		return err
+	}
+
+	u.updates.Lock()
+	defer u.updates.Unlock()
+
+	return u.UserRepository.UpdateUser(user)
+}
+
+func (u UserUseCase) GetUser(id int) (entity.User, error) {
+	return u.UserRepository.GetUser(id)
+}
+
+func (u UserUseCase) GetUsers() ([]entity.User, error) {
+	return u.UserRepository.GetUsers()
+}
+
+// DeleteUser deletes the user together with its devices, so their push
+// tokens don't stay registered.
+func (u UserUseCase) DeleteUser(id int) error {
+	if _, err := u.UserRepository.GetUser(id); err != nil {
+		return err
+	}
+
+	if err := u.DeviceRepository.DeleteDevicesByUser(id); err != nil {
+		return err
+	}
+
+	return u.UserRepository.DeleteUser(id)
+}
+
+func (u UserUseCase) Subscribe(id int, category entity.Category) (entity.User, error) {
+	if !category.IsValid() {
+		return entity.User{}, ValidationError{Message: fmt.Sprintf("invalid category: %s", category)}
+	}
+
+	return u.modify(id, func(user *entity.User) error {
+		if user.IsSubscribed(category) {
+			return ErrAlreadySubscribed
+		}
+
+		user.Subscribed = append(user.Subscribed, category)
+		return nil
+	})
+}
+
+func (u UserUseCase) Unsubscribe(id int, category entity.Category) (entity.User, error) {
+	return u.modify(id, func(user *entity.User) error {
+		if !user.IsSubscribed(category) {
+			return ErrNotSubscribed
+		}
+
+		subscribed := make([]entity.Category, 0, len(user.Subscribed))
+		for _, current := range user.Subscribed {
+			if current != category {
+				subscribed = append(subscribed, current)
+			}
+		}
+
+		user.Subscribed = subscribed
+		return nil
+	})
+}
+
+func (u UserUseCase) EnableChannel(id int, channel entity.Channel) (entity.User, error) {
+	if !channel.IsValid() {
+		return entity.User{}, ValidationError{Message: fmt.Sprintf("invalid channel: %s", channel)}
+	}
+
+	return u.modify(id, func(user *entity.User) error {
+		if user.HasChannel(channel) {
+			return ErrChannelEnabled
+		}
+
+		user.Channels = append(user.Channels, channel)
+		return validateUser(*user)
+	})
+}
+
+// DisableChannel removes the channel from the user's channels and from its
+// fallback chain.
+func (u UserUseCase) DisableChannel(id int, channel entity.Channel) (entity.User, error) {
+	return u.modify(id, func(user *entity.User) error {
+		if !user.HasChannel(channel) {
+			return ErrChannelDisabled
+		}
+
+		user.Channels = withoutChannel(user.Channels, channel)
+		if user.Fallback != nil {
+			user.Fallback = withoutChannel(user.Fallback, channel)
+		}
+		return validateUser(*user)
+	})
+}
+
+func withoutChannel(channels []entity.Channel, channel entity.Channel) []entity.Channel {
+	remaining := make([]entity.Channel, 0, len(channels))
+	for _, current := range channels {
+		if current != channel {
+			remaining = append(remaining, current)
+		}
+	}
+
+	return remaining
+}
+
+// modify applies change to the stored user and saves it, unless change
+// fails.
+func (u UserUseCase) modify(id int, change func(user *entity.User) error) (entity.User, error) {
+	u.updates.Lock()
+	defer u.updates.Unlock()
+
+	user, err := u.UserRepository.GetUser(id)
+	if err != nil {
+		return user, err
+	}
+
+	if err := change(&user); err != nil {
+		return user, err
+	}
+
+	return user, u.UserRepository.UpdateUser(user)
+}
+
+func (u UserUseCase) GetDevices(id int) ([]entity.Device, error) {
+	if _, err := u.UserRepository.GetUser(id); err != nil {
+		return nil, err
+	}
+
+	return u.DeviceRepository.GetDevicesByUser(id)
+}
+
+func (u UserUseCase) RegisterDevice(id int, device entity.Device) (entity.Device, error) {
+	if device.Token == "" {
+		return device, ValidationError{Message: "token is required"}
+	}
+
+	if !device.Platform.IsValid() {
+		return device, ValidationError{Message: fmt.Sprintf("invalid platform: %s", device.Platform)}
+	}
+
+	if _, err := u.UserRepository.GetUser(id); err != nil {
+		return device, err
+	}
+
+	device.UserID = id
+	device.LastSeen = time.Now()
+	return device, u.DeviceRepository.SaveDevice(device)
+}
+
+func (u UserUseCase) UnregisterDevice(id int, token string) error {
+	devices, err := u.GetDevices(id)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		if device.Token == token {
+			return u.DeviceRepository.DeleteDevice(token)
+		}
+	}
+
+	return log.ErrDeviceNotFound
+}
+
+func validateUser(user entity.User) error {
+	if user.ID < 0 {
+		return ValidationError{Message: "id must not be negative"}
+	}
+
+	if user.Name == "" {
+		return ValidationError{Message: "name is required"}
+	}
+
+	// Only a bare address is accepted, since it is used as the SMTP
+	// recipient as it is.
+	if user.Email != "" {
+		if address, err := mail.ParseAddress(user.Email); err != nil || address.Address != user.Email {
+			return ValidationError{Message: fmt.Sprintf("invalid email: %s", user.Email)}
+		}
+	}
+
+	if user.Locale != "" && !entity.IsValidLocale(user.Locale) {
+		return ValidationError{Message: fmt.Sprintf("invalid locale: %s", user.Locale)}
+	}
+
+	if _, err := user.Location(); err != nil {
+		return ValidationError{Message: fmt.Sprintf("invalid timezone: %s", user.Timezone)}
+	}
+
+	if user.QuietHours != nil && !user.QuietHours.IsValid() {
+		return ValidationError{Message: "quiet hours need different start and end times such as 22:00"}
+	}
+
+	for _, category := range user.Subscribed {
+		if !category.IsValid() {
+			return ValidationError{Message: fmt.Sprintf("invalid category: %s", category)}
+		}
+	}
+
+	for _, channel := range user.Channels {
+		if !channel.IsValid() {
+			return ValidationError{Message: fmt.Sprintf("invalid channel: %s", channel)}
+		}
+		if channel == entity.EmailChannel && user.Email == "" {
+			return ValidationError{Message: "email is required for the Email channel"}
+		}
+		if channel == entity.SMSChannel && user.PhoneNumber == "" {
+			return ValidationError{Message: "phone number is required for the SMS channel"}
+		}
+	}
+
+	for i, channel := range user.Fallback {
+		if !user.HasChannel(channel) {
+			return ValidationError{Message: fmt.Sprintf("fallback channel %s is not enabled", channel)}
+		}
+		for _, previous := range user.Fallback[:i] {
+			if previous == channel {
+				return ValidationError{Message: fmt.Sprintf("fallback channel %s is repeated", channel)}
+			}
+		}
+	}
+
+	return nil
+}
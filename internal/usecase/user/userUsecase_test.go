@@ -0,0 +1,245 @@
+package user
+
+import (
+	"notification/internal/entity"
+	repositories "notification/internal/platform/repositories"
+	log "notification/test/platform"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUser_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	service := NewUserUseCase(userEntity, log.NewMockDevice(controller))
+
+	user := getUser(0)
+	userEntity.EXPECT().CreateUser(user).Return(getUser(1), nil)
+
+	created, err := service.CreateUser(user)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.ID)
+}
+
+func TestCreateUser_Validation_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	service := NewUserUseCase(log.NewMockUser(controller), log.NewMockDevice(controller))
+
+	user := getUser(0)
+	user.Name = ""
+	_, err := service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.Subscribed = []entity.Category{"Cooking"}
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.PhoneNumber = ""
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.Email = "not an email"
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.Email = "Mary Alexander <mary.alexander@outlook.com>"
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.Locale = "Portuguese"
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.Timezone = "Mars/Olympus_Mons"
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.QuietHours = &entity.QuietHours{Start: "22:00", End: "7am"}
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	user = getUser(0)
+	user.Fallback = []entity.Channel{entity.PushChannel, entity.SMSChannel}
+	_, err = service.CreateUser(user)
+	assert.ErrorAs(t, err, &ValidationError{})
+}
+
+func TestSubscribe_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	service := NewUserUseCase(userEntity, log.NewMockDevice(controller))
+
+	expected := getUser(1)
+	expected.Subscribed = append(expected.Subscribed, entity.MoviesCategory)
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	userEntity.EXPECT().UpdateUser(expected).Return(nil)
+
+	user, err := service.Subscribe(1, entity.MoviesCategory)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, user)
+}
+
+func TestSubscribe_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	service := NewUserUseCase(userEntity, log.NewMockDevice(controller))
+
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	_, err := service.Subscribe(1, entity.SportsCategory)
+	assert.ErrorIs(t, err, ErrAlreadySubscribed)
+
+	userEntity.EXPECT().GetUser(2).Return(entity.User{}, repositories.ErrUserNotFound)
+	_, err = service.Subscribe(2, entity.SportsCategory)
+	assert.ErrorIs(t, err, repositories.ErrUserNotFound)
+
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	_, err = service.Unsubscribe(1, entity.FinanceCategory)
+	assert.ErrorIs(t, err, ErrNotSubscribed)
+}
+
+func TestSubscribe_Concurrent_Success(t *testing.T) {
+	userRepository := repositories.NewUserRepository(t.TempDir() + "/users.json")
+	service := NewUserUseCase(userRepository, nil)
+
+	_, err := userRepository.CreateUser(getUser(1))
+	assert.NoError(t, err)
+
+	var wait sync.WaitGroup
+	for _, category := range []entity.Category{entity.FinanceCategory, entity.MoviesCategory} {
+		wait.Add(1)
+		go func(category entity.Category) {
+			defer wait.Done()
+			_, err := service.Subscribe(1, category)
+			assert.NoError(t, err)
+		}(category)
+	}
+	wait.Wait()
+
+	user, err := userRepository.GetUser(1)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, entity.Categories, user.Subscribed)
+}
+
+func TestChannels_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	service := NewUserUseCase(userEntity, log.NewMockDevice(controller))
+
+	enabled := getUser(1)
+	enabled.Channels = append(enabled.Channels, entity.EmailChannel)
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	userEntity.EXPECT().UpdateUser(enabled).Return(nil)
+
+	_, err := service.EnableChannel(1, entity.EmailChannel)
+	assert.NoError(t, err)
+
+	// The disabled channel leaves the fallback chain too.
+	enabled.Fallback = []entity.Channel{entity.SMSChannel, entity.EmailChannel}
+	disabled := enabled
+	disabled.Channels = []entity.Channel{entity.EmailChannel}
+	disabled.Fallback = []entity.Channel{entity.EmailChannel}
+	userEntity.EXPECT().GetUser(1).Return(enabled, nil)
+	userEntity.EXPECT().UpdateUser(disabled).Return(nil)
+
+	_, err = service.DisableChannel(1, entity.SMSChannel)
+	assert.NoError(t, err)
+
+	userEntity.EXPECT().GetUser(1).Return(disabled, nil)
+	_, err = service.EnableChannel(1, entity.EmailChannel)
+	assert.ErrorIs(t, err, ErrChannelEnabled)
+}
+
+func TestDeleteUser_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	deviceEntity := log.NewMockDevice(controller)
+	service := NewUserUseCase(userEntity, deviceEntity)
+
+	gomock.InOrder(
+		userEntity.EXPECT().GetUser(1).Return(getUser(1), nil),
+		deviceEntity.EXPECT().DeleteDevicesByUser(1).Return(nil),
+		userEntity.EXPECT().DeleteUser(1).Return(nil),
+	)
+	assert.NoError(t, service.DeleteUser(1))
+
+	userEntity.EXPECT().GetUser(2).Return(entity.User{}, repositories.ErrUserNotFound)
+	assert.ErrorIs(t, service.DeleteUser(2), repositories.ErrUserNotFound)
+}
+
+func TestRegisterDevice_Success(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	deviceEntity := log.NewMockDevice(controller)
+	service := NewUserUseCase(userEntity, deviceEntity)
+
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	deviceEntity.EXPECT().SaveDevice(gomock.Any()).Return(nil)
+
+	device, err := service.RegisterDevice(1, entity.Device{Token: "token", Platform: entity.IOSPlatform})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, device.UserID)
+	assert.False(t, device.LastSeen.IsZero())
+
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	deviceEntity.EXPECT().GetDevicesByUser(1).Return([]entity.Device{device}, nil)
+	deviceEntity.EXPECT().DeleteDevice("token").Return(nil)
+
+	err = service.UnregisterDevice(1, "token")
+	assert.NoError(t, err)
+}
+
+func TestRegisterDevice_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+	userEntity := log.NewMockUser(controller)
+	deviceEntity := log.NewMockDevice(controller)
+	service := NewUserUseCase(userEntity, deviceEntity)
+
+	_, err := service.RegisterDevice(1, entity.Device{Token: "token", Platform: "Symbian"})
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	_, err = service.RegisterDevice(1, entity.Device{Platform: entity.IOSPlatform})
+	assert.ErrorAs(t, err, &ValidationError{})
+
+	userEntity.EXPECT().GetUser(1).Return(getUser(1), nil)
+	deviceEntity.EXPECT().GetDevicesByUser(1).Return(nil, nil)
+
+	err = service.UnregisterDevice(1, "token")
+	assert.ErrorIs(t, err, repositories.ErrDeviceNotFound)
+}
+
+func getUser(id int) entity.User {
+	return entity.User{
+		ID:          id,
+		Name:        "Mary Alexander",
+		Email:       "mary.alexander@outlook.com",
+		PhoneNumber: "78958745",
+		Subscribed:  []entity.Category{entity.SportsCategory},
+		Channels:    []entity.Channel{entity.SMSChannel},
+	}
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/platform/repositories/deadLetter.go
+
+// Package log is a generated GoMock package.
+package log
+
+import (
+	entity "notification/internal/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeadLetter is a mock of DeadLetter interface.
+type MockDeadLetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterMockRecorder
+}
+
+// MockDeadLetterMockRecorder is the mock recorder for MockDeadLetter.
+type MockDeadLetterMockRecorder struct {
+	mock *MockDeadLetter
+}
+
+// NewMockDeadLetter creates a new mock instance.
+func NewMockDeadLetter(ctrl *gomock.Controller) *MockDeadLetter {
+	mock := &MockDeadLetter{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetter) EXPECT() *MockDeadLetterMockRecorder {
+	return m.recorder
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockDeadLetter) DeleteDeadLetter(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockDeadLetterMockRecorder) DeleteDeadLetter(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockDeadLetter)(nil).DeleteDeadLetter), id)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockDeadLetter) GetDeadLetter(id string) (entity.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", id)
+	ret0, _ := ret[0].(entity.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockDeadLetterMockRecorder) GetDeadLetter(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockDeadLetter)(nil).GetDeadLetter), id)
+}
+
+// GetDeadLetters mocks base method.
+func (m *MockDeadLetter) GetDeadLetters() ([]entity.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetters")
+	ret0, _ := ret[0].([]entity.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetters indicates an expected call of GetDeadLetters.
+func (mr *MockDeadLetterMockRecorder) GetDeadLetters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetters", reflect.TypeOf((*MockDeadLetter)(nil).GetDeadLetters))
+}
+
+// SaveDeadLetter mocks base method.
+func (m *MockDeadLetter) SaveDeadLetter(deadLetter entity.DeadLetter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDeadLetter", deadLetter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDeadLetter indicates an expected call of SaveDeadLetter.
+func (mr *MockDeadLetterMockRecorder) SaveDeadLetter(deadLetter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeadLetter", reflect.TypeOf((*MockDeadLetter)(nil).SaveDeadLetter), deadLetter)
+}
@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/platform/repositories/device.go
+
+// Package log is a generated GoMock package.
+package log
+
+import (
+	entity "notification/internal/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDevice is a mock of Device interface.
+type MockDevice struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceMockRecorder
+}
+
+// MockDeviceMockRecorder is the mock recorder for MockDevice.
+type MockDeviceMockRecorder struct {
+	mock *MockDevice
+}
+
+// NewMockDevice creates a new mock instance.
+func NewMockDevice(ctrl *gomock.Controller) *MockDevice {
+	mock := &MockDevice{ctrl: ctrl}
+	mock.recorder = &MockDeviceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDevice) EXPECT() *MockDeviceMockRecorder {
+	return m.recorder
+}
+
+// DeleteDevice mocks base method.
+func (m *MockDevice) DeleteDevice(token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDevice", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDevice indicates an expected call of DeleteDevice.
+func (mr *MockDeviceMockRecorder) DeleteDevice(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDevice", reflect.TypeOf((*MockDevice)(nil).DeleteDevice), token)
+}
+
+// DeleteDevicesByUser mocks base method.
+func (m *MockDevice) DeleteDevicesByUser(userID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDevicesByUser", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDevicesByUser indicates an expected call of DeleteDevicesByUser.
+func (mr *MockDeviceMockRecorder) DeleteDevicesByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDevicesByUser", reflect.TypeOf((*MockDevice)(nil).DeleteDevicesByUser), userID)
+}
+
+// GetDevicesByUser mocks base method.
+func (m *MockDevice) GetDevicesByUser(userID int) ([]entity.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDevicesByUser", userID)
+	ret0, _ := ret[0].([]entity.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDevicesByUser indicates an expected call of GetDevicesByUser.
+func (mr *MockDeviceMockRecorder) GetDevicesByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDevicesByUser", reflect.TypeOf((*MockDevice)(nil).GetDevicesByUser), userID)
+}
+
+// SaveDevice mocks base method.
+func (m *MockDevice) SaveDevice(device entity.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDevice", device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDevice indicates an expected call of SaveDevice.
+func (mr *MockDeviceMockRecorder) SaveDevice(device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDevice", reflect.TypeOf((*MockDevice)(nil).SaveDevice), device)
+}
@@ -35,32 +35,49 @@ func (m *MockLog) EXPECT() *MockLogMockRecorder {
 }
 
 // DeleteLogs mocks base method.
-func (m *MockLog) DeleteLogs() error {
+func (m *MockLog) DeleteLogs(filter entity.LogFilter) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLogs")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteLogs", filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteLogs indicates an expected call of DeleteLogs.
-func (mr *MockLogMockRecorder) DeleteLogs() *gomock.Call {
+func (mr *MockLogMockRecorder) DeleteLogs(filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogs", reflect.TypeOf((*MockLog)(nil).DeleteLogs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogs", reflect.TypeOf((*MockLog)(nil).DeleteLogs), filter)
 }
 
 // GetLogs mocks base method.
-func (m *MockLog) GetLogs() ([]entity.Log, error) {
+func (m *MockLog) GetLogs(filter entity.LogFilter) ([]entity.Log, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLogs")
+	ret := m.ctrl.Call(m, "GetLogs", filter)
 	ret0, _ := ret[0].([]entity.Log)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLogs indicates an expected call of GetLogs.
-func (mr *MockLogMockRecorder) GetLogs() *gomock.Call {
+func (mr *MockLogMockRecorder) GetLogs(filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockLog)(nil).GetLogs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockLog)(nil).GetLogs), filter)
+}
+
+// GetLogsPage mocks base method.
+func (m *MockLog) GetLogsPage(filter entity.LogFilter) ([]entity.Log, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogsPage", filter)
+	ret0, _ := ret[0].([]entity.Log)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLogsPage indicates an expected call of GetLogsPage.
+func (mr *MockLogMockRecorder) GetLogsPage(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogsPage", reflect.TypeOf((*MockLog)(nil).GetLogsPage), filter)
 }
 
 // SaveLog mocks base method.
@@ -76,3 +93,17 @@ func (mr *MockLogMockRecorder) SaveLog(log interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLog", reflect.TypeOf((*MockLog)(nil).SaveLog), log)
 }
+
+// UpdateLog mocks base method.
+func (m *MockLog) UpdateLog(log entity.Log) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLog", log)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLog indicates an expected call of UpdateLog.
+func (mr *MockLogMockRecorder) UpdateLog(log interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLog", reflect.TypeOf((*MockLog)(nil).UpdateLog), log)
+}